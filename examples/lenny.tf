@@ -27,7 +27,7 @@ resource "wistia_media" "my_first_terraformed_media" {
 
 resource "wistia_media" "my_second_terraformed_media" {
   name       = "Lenny terraforms Mars again"
-  file       = "lenny-mars.mp4"
+  url        = "https://embed-ssl.wistia.com/deliveries/57be37488565a5e51351e5ecebcea610.bin"
   project_id = wistia_project.my_first_terraformed_project.hashed_id
 }
 
@@ -40,6 +40,6 @@ resource "wistia_project" "my_second_terraformed_project" {
 
 resource "wistia_media" "my_third_terraformed_media" {
   name       = "Lenny terraforms Wistia HQ"
-  file       = "lenny-wistia-hq.mp4"
+  url        = "https://embed-ssl.wistia.com/deliveries/57be37488565a5e51351e5ecebcea610.bin"
   project_id = wistia_project.my_second_terraformed_project.hashed_id
 }
\ No newline at end of file
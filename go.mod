@@ -5,4 +5,5 @@ go 1.14
 require (
 	github.com/hashicorp/terraform-plugin-docs v0.5.0 // indirect
 	github.com/hashicorp/terraform-plugin-sdk/v2 v2.8.0
+	golang.org/x/oauth2 v0.0.0-20200107190931-bf48bf16ab8d
 )
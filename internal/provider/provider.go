@@ -2,9 +2,14 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/wistia/terraform-provider-wistia/internal/wistia"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	"net"
 	"net/http"
 	"time"
 )
@@ -18,16 +23,63 @@ func New() func() *schema.Provider {
 		return &schema.Provider{
 			ConfigureContextFunc: configureProvider,
 			ResourcesMap: map[string]*schema.Resource{
+				"wistia_caption":             captionResource(),
+				"wistia_caption_order":       captionOrderResource(),
 				"wistia_media":               mediaResource(),
+				"wistia_media_asset_file":    mediaAssetFileResource(),
+				"wistia_media_copy":          mediaCopyResource(),
 				"wistia_media_customization": customizationResource(),
+				"wistia_media_thumbnail":     mediaThumbnailResource(),
 				"wistia_project":             projectResource(),
 			},
 			Schema: map[string]*schema.Schema{
 				"access_token": {
 					Type:        schema.TypeString,
-					Required:    true,
+					Optional:    true,
+					Sensitive:   true,
 					DefaultFunc: schema.EnvDefaultFunc("WISTIA_ACCESS_TOKEN", nil),
-					Description: "Wistia access token with read, update, delete, and upload permissions",
+					Description: "Wistia access token with read, update, delete, and upload permissions. Required unless `oauth2` is configured.",
+				},
+				"oauth2": {
+					Type:        schema.TypeList,
+					Optional:    true,
+					MaxItems:    1,
+					Description: "Obtain access tokens through OAuth2 instead of using a long-lived `access_token`. Tokens are refreshed automatically when they expire. Uses the client credentials flow, or the refresh token flow if `refresh_token` is set.",
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"client_id": {
+								Type:        schema.TypeString,
+								Required:    true,
+								DefaultFunc: schema.EnvDefaultFunc("WISTIA_CLIENT_ID", nil),
+								Description: "OAuth2 client ID.",
+							},
+							"client_secret": {
+								Type:        schema.TypeString,
+								Required:    true,
+								Sensitive:   true,
+								DefaultFunc: schema.EnvDefaultFunc("WISTIA_CLIENT_SECRET", nil),
+								Description: "OAuth2 client secret.",
+							},
+							"token_url": {
+								Type:        schema.TypeString,
+								Required:    true,
+								Description: "URL of the OAuth2 token endpoint.",
+							},
+							"refresh_token": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Sensitive:   true,
+								DefaultFunc: schema.EnvDefaultFunc("WISTIA_REFRESH_TOKEN", nil),
+								Description: "Refresh token to exchange for access tokens. If unset, the client credentials flow is used.",
+							},
+							"scopes": {
+								Type:        schema.TypeList,
+								Optional:    true,
+								Elem:        &schema.Schema{Type: schema.TypeString},
+								Description: "Scopes to request.",
+							},
+						},
+					},
 				},
 				"environment": {
 					Type:        schema.TypeString,
@@ -35,25 +87,167 @@ func New() func() *schema.Provider {
 					DefaultFunc: schema.EnvDefaultFunc("WISTIA_ENV", "production"),
 					Description: "Wistia environment to use [production (default), staging]",
 				},
+				"max_retries": {
+					Type:        schema.TypeInt,
+					Optional:    true,
+					Default:     wistia.DefaultRetryPolicy.MaxRetries,
+					Description: "Maximum number of times a request that fails with a 429 or 5xx response is retried, honoring any `Retry-After` sent by the API. Requests that create something, such as uploads, are only retried on a 429, a 503 or a response with `Retry-After`, so that they aren't carried out twice. Set to 0 to disable retries. Defaults to 4.",
+				},
+				"requests_per_second": {
+					Type:         schema.TypeFloat,
+					Optional:     true,
+					Default:      10.0,
+					ValidateFunc: validation.FloatAtLeast(0),
+					Description:  "Maximum sustained rate of requests sent to Wistia, shared by all resources. The rate is lowered temporarily whenever the API responds with 429. Set to 0 to disable client-side rate limiting. Defaults to 10.",
+				},
+				"request_burst": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      10,
+					ValidateFunc: validation.IntAtLeast(1),
+					Description:  "Maximum number of requests that may be sent at once before `requests_per_second` applies. Defaults to 10.",
+				},
+				"api_connect_timeout": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "10s",
+					ValidateFunc: validateDuration,
+					Description:  "Maximum time to wait for a connection to the Wistia API, as a duration like `30s`. Defaults to `10s`.",
+				},
+				"api_response_header_timeout": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "10s",
+					ValidateFunc: validateDuration,
+					Description:  "Maximum time to wait for the Wistia API to send response headers after a request has been sent. Defaults to `10s`.",
+				},
+				"api_timeout": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "60s",
+					ValidateFunc: validateDuration,
+					Description:  "Maximum time for a single Wistia API request, including reading the response. Set to `0` for no limit. Defaults to `60s`.",
+				},
+				"upload_connect_timeout": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "10s",
+					ValidateFunc: validateDuration,
+					Description:  "Maximum time to wait for a connection to the Wistia upload endpoint. Defaults to `10s`.",
+				},
+				"upload_response_header_timeout": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "10m",
+					ValidateFunc: validateDuration,
+					Description:  "Maximum time to wait for the upload endpoint to respond once a file has been sent completely. Large files can take a while to be accepted. Set to `0` for no limit. Defaults to `10m`.",
+				},
+				"upload_max_bytes_per_second": {
+					Type:         schema.TypeInt,
+					Optional:     true,
+					Default:      0,
+					ValidateFunc: validation.IntAtLeast(0),
+					Description:  "Maximum combined bandwidth, in bytes per second, of all `file` uploads made by this provider. Set to 0 for no limit. Defaults to 0.",
+				},
+				"upload_timeout": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "0",
+					ValidateFunc: validateDuration,
+					Description:  "Maximum time for a single upload, including sending the file. Set to `0` for no limit. Defaults to `0`.",
+				},
 			},
 		}
 	}
 }
 
 func configureProvider(_ context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
-	accessToken := d.Get("access_token").(string)
 	environment := d.Get("environment").(string)
-	httpClient := &http.Client{
-		Transport: &http.Transport{
-			TLSHandshakeTimeout:   10 * time.Second,
-			ResponseHeaderTimeout: 10 * time.Second,
-			ExpectContinueTimeout: 1 * time.Second,
-		},
+	httpClient := newHTTPClient(d, "api")
+	tokenSource, err := tokenSourceFromResource(d, httpClient)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+	wistiaClient := wistia.NewClient(httpClient, tokenSource)
+	wistiaClient.UploadHTTPClient = newHTTPClient(d, "upload")
+	if bps := d.Get("upload_max_bytes_per_second").(int); bps > 0 {
+		// Allow a second's worth of data to be sent at once so that reads aren't split into tiny pieces.
+		wistiaClient.UploadBandwidthLimiter = wistia.NewRateLimiter(float64(bps), bps)
+	}
+	wistiaClient.RetryPolicy.MaxRetries = d.Get("max_retries").(int)
+	if rps := d.Get("requests_per_second").(float64); rps > 0 {
+		wistiaClient.RateLimiter = wistia.NewRateLimiter(rps, d.Get("request_burst").(int))
 	}
-	wistiaClient := wistia.NewClient(httpClient, accessToken)
 	if environment == "staging" {
 		wistiaClient.APIBaseEndpoint = "https://api.wistia.st/v1/"
 		wistiaClient.UploadBaseEndpoint = "https://upload-v2.wistia.st/"
 	}
 	return wistiaClient, nil
 }
+
+func tokenSourceFromResource(d *schema.ResourceData, httpClient *http.Client) (wistia.TokenSource, error) {
+	oauth2Config := d.Get("oauth2").([]interface{})
+	if len(oauth2Config) == 0 || oauth2Config[0] == nil {
+		accessToken := d.Get("access_token").(string)
+		if accessToken == "" {
+			return nil, fmt.Errorf("either access_token or an oauth2 block must be configured")
+		}
+		return wistia.StaticTokenSource(accessToken), nil
+	}
+
+	c := oauth2Config[0].(map[string]interface{})
+	var scopes []string
+	for _, scope := range c["scopes"].([]interface{}) {
+		scopes = append(scopes, scope.(string))
+	}
+	// Tokens are refreshed long after configuration is done, so this mustn't use the configure context.
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, httpClient)
+	if refreshToken := c["refresh_token"].(string); refreshToken != "" {
+		config := &oauth2.Config{
+			ClientID:     c["client_id"].(string),
+			ClientSecret: c["client_secret"].(string),
+			Endpoint:     oauth2.Endpoint{TokenURL: c["token_url"].(string)},
+			Scopes:       scopes,
+		}
+		return config.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}), nil
+	}
+	config := &clientcredentials.Config{
+		ClientID:     c["client_id"].(string),
+		ClientSecret: c["client_secret"].(string),
+		TokenURL:     c["token_url"].(string),
+		Scopes:       scopes,
+	}
+	return config.TokenSource(ctx), nil
+}
+
+// newHTTPClient builds an HTTP client from the provider's timeout settings with the given prefix.
+func newHTTPClient(d *schema.ResourceData, prefix string) *http.Client {
+	duration := func(key string) time.Duration {
+		// Values have already been checked by validateDuration.
+		value, _ := time.ParseDuration(d.Get(prefix + "_" + key).(string))
+		return value
+	}
+	return &http.Client{
+		Timeout: duration("timeout"),
+		Transport: &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout:   duration("connect_timeout"),
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ResponseHeaderTimeout: duration("response_header_timeout"),
+			ExpectContinueTimeout: 1 * time.Second,
+		},
+	}
+}
+
+func validateDuration(i interface{}, k string) ([]string, []error) {
+	value, err := time.ParseDuration(i.(string))
+	if err != nil {
+		return nil, []error{fmt.Errorf("expected %s to be a duration like 30s or 5m: %s", k, err)}
+	}
+	if value < 0 {
+		return nil, []error{fmt.Errorf("expected %s not to be negative", k)}
+	}
+	return nil, nil
+}
@@ -0,0 +1,197 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/wistia/terraform-provider-wistia/internal/wistia"
+	"io/ioutil"
+	"log"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+func captionResource() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: createCaption,
+		ReadContext:   readCaption,
+		UpdateContext: updateCaption,
+		DeleteContext: deleteCaption,
+		Description:   "Captions for a Wistia media in one language. See the [API documentation](https://wistia.com/support/developers/data-api#captions) for more details.",
+		CustomizeDiff: customizeCaptionDiff,
+
+		Schema: map[string]*schema.Schema{
+			"media_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The hashed ID of the media the captions are for.",
+			},
+			"language": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[a-z]{3}$`), "must be a 3-letter ISO-639-2 language code"),
+				Description:  "The 3-letter ISO-639-2 code of the captions' language, e.g. `eng`.",
+			},
+			"text": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"text", "file"},
+				Description:  "The captions in SRT format.",
+			},
+			"file": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"text", "file"},
+				Description:  "A path to an SRT file on disk with the captions.",
+			},
+			"content_hash": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The SHA-256 of the captions, ignoring differences in formatting such as line endings and whitespace. It's computed when planning, and compared with the captions in Wistia to detect changes made outside of Terraform.",
+			},
+			"english_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The English name of the captions' language.",
+			},
+			"native_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the captions' language in that language.",
+			},
+		},
+	}
+}
+
+func createCaption(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	wc := m.(*wistia.Client)
+	mediaId := d.Get("media_id").(string)
+	caption, err := captionFromResource(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := wc.Captions.Create(ctx, mediaId, caption); err != nil {
+		return diag.Errorf("couldn't create %s captions for media %s: %s", caption.Language, mediaId, err)
+	}
+
+	d.SetId(mediaId + "/" + caption.Language)
+
+	return readCaption(ctx, d, m)
+}
+
+func readCaption(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	wc := m.(*wistia.Client)
+	mediaId, language := d.Get("media_id").(string), d.Get("language").(string)
+	caption, err := wc.Captions.Get(ctx, mediaId, language)
+	if errors.Is(err, wistia.ErrNotFound) {
+		log.Printf("[WARN] Wistia %s captions for media %s not found, removing from state", language, mediaId)
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("couldn't get captions: %s", err)
+	}
+
+	d.Set("content_hash", captionHash(caption.Text))
+	d.Set("english_name", caption.EnglishName)
+	d.Set("native_name", caption.NativeName)
+
+	return nil
+}
+
+func updateCaption(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	wc := m.(*wistia.Client)
+	mediaId := d.Get("media_id").(string)
+	caption, err := captionFromResource(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := wc.Captions.Update(ctx, mediaId, caption); err != nil {
+		return diag.Errorf("couldn't update %s captions for media %s: %s", caption.Language, mediaId, err)
+	}
+
+	return readCaption(ctx, d, m)
+}
+
+func deleteCaption(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	wc := m.(*wistia.Client)
+	err := wc.Captions.Delete(ctx, d.Get("media_id").(string), d.Get("language").(string))
+	if err != nil && !errors.Is(err, wistia.ErrNotFound) {
+		return diag.Errorf("couldn't delete captions: %s", err)
+	}
+
+	return nil
+}
+
+// Private helpers
+
+func customizeCaptionDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if !d.NewValueKnown("text") || !d.NewValueKnown("file") {
+		return d.SetNewComputed("content_hash")
+	}
+	text, err := captionText(d.Get("text").(string), d.Get("file").(string))
+	if err != nil {
+		return err
+	}
+	if hash := captionHash(text); hash != d.Get("content_hash").(string) {
+		return d.SetNew("content_hash", hash)
+	}
+	return nil
+}
+
+func captionFromResource(d *schema.ResourceData) (*wistia.Caption, error) {
+	text, err := captionText(d.Get("text").(string), d.Get("file").(string))
+	if err != nil {
+		return nil, err
+	}
+	return &wistia.Caption{Language: d.Get("language").(string), Text: text}, nil
+}
+
+// captionText returns text, or the contents of filePath if it's set.
+func captionText(text, filePath string) (string, error) {
+	if filePath == "" {
+		return text, nil
+	}
+	contents, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("couldn't read captions file '%s': %s", filePath, err)
+	}
+	return string(contents), nil
+}
+
+// captionHash hashes captions after normalizing them, so that the captions as configured and as Wistia stores them
+// hash the same.
+func captionHash(text string) string {
+	h := sha256.Sum256([]byte(normalizeCaptions(text)))
+	return hex.EncodeToString(h[:])
+}
+
+var (
+	blankLinesPattern = regexp.MustCompile(`\n{3,}`)
+	// SRT separates seconds from milliseconds with a comma, but WebVTT-style periods are common too.
+	srtTimestampPattern = regexp.MustCompile(`(\d{2}:\d{2}:\d{2})\.(\d{3})`)
+)
+
+// normalizeCaptions removes the differences in formatting that Wistia doesn't preserve: a byte order mark, line
+// endings, trailing whitespace, runs of blank lines, and the millisecond separator in timestamps.
+func normalizeCaptions(text string) string {
+	text = strings.TrimPrefix(text, "\ufeff")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRightFunc(line, unicode.IsSpace)
+	}
+	text = strings.Join(lines, "\n")
+	text = blankLinesPattern.ReplaceAllString(text, "\n\n")
+	text = srtTimestampPattern.ReplaceAllString(text, "$1,$2")
+	return strings.TrimSpace(text)
+}
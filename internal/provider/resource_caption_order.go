@@ -0,0 +1,144 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/wistia/terraform-provider-wistia/internal/wistia"
+	"log"
+	"time"
+)
+
+func captionOrderResource() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: createCaptionOrder,
+		ReadContext:   readCaptionOrder,
+		UpdateContext: updateCaptionOrder,
+		DeleteContext: deleteCaptionOrder,
+		Description:   "Orders captions for a Wistia media from Wistia, once the media has been processed. Nothing is ordered if the media already has captions. Purchases are billed to the account, and destroying this resource doesn't delete the captions. See the [API documentation](https://wistia.com/support/developers/data-api#captions_purchase) for more details.",
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(2 * time.Hour),
+		},
+		CustomizeDiff: customizeCaptionOrderDiff,
+
+		Schema: map[string]*schema.Schema{
+			"media_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The hashed ID of the media to order captions for.",
+			},
+			"automated": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+				Description: "Whether to order machine-generated captions. If false, the captions are transcribed by a person, which costs more and can take days. Defaults to `true`.",
+			},
+			"wait_for_captions": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Whether to wait until the captions have been added to the media. If they don't arrive before the `create` timeout, the order is kept and a warning is reported. Defaults to `true` for machine-generated captions, and `false` otherwise.",
+			},
+			"languages": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The languages the media has captions in.",
+			},
+		},
+	}
+}
+
+func createCaptionOrder(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	wc := m.(*wistia.Client)
+	mediaId := d.Get("media_id").(string)
+
+	// Captions can only be ordered for media that has been processed.
+	if _, err := wc.Media.WaitForStatus(ctx, mediaId, wistia.MediaStatusReady); err != nil {
+		return diag.Errorf("media %s isn't ready: %s", mediaId, err)
+	}
+
+	captions, err := wc.Captions.List(ctx, mediaId)
+	if err != nil {
+		return diag.Errorf("couldn't list captions for media %s: %s", mediaId, err)
+	}
+	if len(captions) > 0 {
+		log.Printf("[INFO] Media %s already has captions; not ordering any", mediaId)
+	} else {
+		if err := wc.Captions.Purchase(ctx, mediaId, d.Get("automated").(bool)); err != nil {
+			return diag.Errorf("couldn't order captions for media %s: %s", mediaId, err)
+		}
+		log.Printf("[DEBUG] Ordered captions for media %s", mediaId)
+	}
+
+	d.SetId(mediaId)
+	d.Set("languages", captionLanguages(captions))
+
+	if len(captions) == 0 && d.Get("wait_for_captions").(bool) {
+		// The order has been placed, so failing here would taint the resource and order the captions again.
+		ordered, err := wc.Captions.WaitForCaptions(ctx, mediaId)
+		if err != nil {
+			return diag.Diagnostics{{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("Captions for media %s aren't available yet", mediaId),
+				Detail:   fmt.Sprintf("The captions were ordered, but didn't arrive in time: %s. They'll show up in languages once they do.", err),
+			}}
+		}
+		d.Set("languages", captionLanguages(ordered))
+	}
+
+	return nil
+}
+
+func readCaptionOrder(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	wc := m.(*wistia.Client)
+	captions, err := wc.Captions.List(ctx, d.Id())
+	if errors.Is(err, wistia.ErrNotFound) {
+		log.Printf("[WARN] Wistia media %s not found, removing caption order from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("couldn't list captions: %s", err)
+	}
+
+	d.Set("languages", captionLanguages(captions))
+
+	return nil
+}
+
+func updateCaptionOrder(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Only wait_for_captions can change, and it only matters when the order is placed.
+	return readCaptionOrder(ctx, d, m)
+}
+
+func deleteCaptionOrder(_ context.Context, _ *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	// A purchase can't be undone; the captions stay on the media, and can be removed with the captions API.
+	return nil
+}
+
+// Private helpers
+
+// customizeCaptionOrderDiff defaults wait_for_captions to automated, since captions transcribed by a person can take
+// days.
+func customizeCaptionOrderDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() != "" {
+		return nil
+	}
+	if raw := d.GetRawConfig(); !raw.IsNull() && !raw.GetAttr("wait_for_captions").IsNull() {
+		return nil
+	}
+	return d.SetNew("wait_for_captions", d.Get("automated").(bool))
+}
+
+func captionLanguages(captions []wistia.Caption) []interface{} {
+	languages := make([]interface{}, len(captions))
+	for i, c := range captions {
+		languages[i] = c.Language
+	}
+	return languages
+}
@@ -2,30 +2,79 @@ package provider
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/wistia/terraform-provider-wistia/internal/wistia"
+	"io"
 	"log"
+	"net/http"
 	"os"
 	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
 )
 
 func mediaResource() *schema.Resource {
 	return &schema.Resource{
-		Create: createMedia,
-		Read:   readMedia,
-		Update: updateMedia,
-		Delete: deleteMedia,
+		CreateContext: createMedia,
+		ReadContext:   readMedia,
+		UpdateContext: updateMedia,
+		DeleteContext: deleteMedia,
 		// TODO: Do we need this?
 		//Exists: isMedia,
 		Description: "A Wistia media. See the [API documentation](https://wistia.com/support/developers/data-api#medias) for more details.",
+		Timeouts: &schema.ResourceTimeout{
+			// Uploads of large files can take a long time; the SDK's default of 20 minutes isn't enough.
+			Create: schema.DefaultTimeout(6 * time.Hour),
+			Update: schema.DefaultTimeout(6 * time.Hour),
+		},
+		CustomizeDiff: customdiff.Sequence(validateMediaFile, validateMediaURL, customizeMediaDiff, customizeMediaMoveDiff),
 
 		Schema: map[string]*schema.Schema{
 			"file": {
 				Type:         schema.TypeString,
 				Optional:     true,
 				ExactlyOneOf: []string{"file", "url"},
-				Description:  "A path to a file on disk that will be uploaded to Wistia.",
+				Description:  "A path to a file on disk that will be uploaded to Wistia. When the file's contents change, the media is replaced in place, keeping its hashed ID, embed codes, stats and customizations.",
+			},
+			"max_file_size": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "The largest `file`, in bytes, that may be uploaded, checked when planning. Wistia's limit depends on the account's plan. Defaults to 0, which means no limit.",
+			},
+			"source_hash": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The SHA-256 of `file`, computed when planning. It's also recorded in the media's description, so that if an apply fails after uploading the file, the next one adopts the uploaded media instead of uploading it again. Media already managed by another resource is never adopted.",
+			},
+			"upload_claim": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "A random token recorded next to `source_hash` in the media's description, marking the media as managed by this resource so that no other resource adopts it.",
+			},
+			"source_size":     sourceSizeSchema(),
+			"source_modified": sourceModifiedSchema(),
+			"source_version": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "An arbitrary version for the file at `url`. Since remote files can't be inspected when planning, change this (or `url`) to replace the media in place with the current contents of `url`.",
+			},
+			"check_url": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to check that `url` can be fetched when planning a change to it, by sending it a HEAD request. Defaults to `false`.",
 			},
 			"url": {
 				Type:         schema.TypeString,
@@ -33,6 +82,12 @@ func mediaResource() *schema.Resource {
 				ExactlyOneOf: []string{"file", "url"},
 				Description:  "A URL to a file that will be uploaded to Wistia.",
 			},
+			"wait_for_ready": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Wait for Wistia to finish processing the media before it's considered created, so that resources depending on it don't race against processing. Creation fails if processing ends with the `failed` status. The wait counts towards the `create` timeout.",
+			},
 			"media_id": {
 				Type:        schema.TypeInt,
 				Computed:    true,
@@ -41,12 +96,19 @@ func mediaResource() *schema.Resource {
 			"name": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Description: "The display name of the media.",
+				Computed:    true,
+				Description: "The display name of the media. Defaults to a name Wistia derives from the uploaded file.",
 			},
 			"project_id": {
 				Type:        schema.TypeString,
 				Required:    true,
-				Description: "The identifier for the Wistia project that will host this media.",
+				Description: "The identifier for the Wistia project that will host this media. Changing it moves the media to the new project, keeping its hashed ID. If Wistia can't move it, the apply fails unless `allow_copy_on_move` is set.",
+			},
+			"allow_copy_on_move": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to copy the media to the new project and delete the original when `project_id` changes and Wistia can't move it. The copy has a new hashed ID, and the original's stats, captions and customizations are lost. When set, a change to `project_id` plans `hashed_id` and `media_id` as unknown. Defaults to `false`.",
 			},
 			"type": {
 				Type:        schema.TypeString,
@@ -63,12 +125,27 @@ func mediaResource() *schema.Resource {
 				Computed:    true,
 				Description: "The title of the section in which the media appears. This attribute is omitted if the media is not in a section (default).",
 			},
-			// TODO
-			//"thumbnail": {
-			//	Type:        schema.TypeMap,
-			//	Computed:    true,
-			//  Description: "An object representing the thumbnail for this media. The attributes are URL, width, and height.
-			//},
+			"thumbnail": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The thumbnail for this media.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"url": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"width": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"height": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
 			"duration": {
 				Type:        schema.TypeFloat,
 				Computed:    true,
@@ -84,22 +161,51 @@ func mediaResource() *schema.Resource {
 				Computed:    true,
 				Description: "The date when the media was last changed.",
 			},
-			// TODO
-			//"assets": {
-			//	Type:        schema.TypeList,
-			//	Elem:        wistia.Asset{},
-			//	Computed:    true,
-			//  Description: "An array of the assets available for this media.",
-			//},
-			//"embed_code": {
-			//	Type:       schema.TypeString,
-			//	Computed:   true,
-			//	Deprecated: "If you want to programmatically embed videos, follow the \"construct an embed code\" guide.",
-			//},
-			"description": {
+			"assets": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The assets available for this media, such as the original file and its renditions.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"url": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"content_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"file_size": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"width": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"height": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"embed_code": {
 				Type:        schema.TypeString,
 				Computed:    true,
-				Description: "The description for the media which usually appears near teh top of the sidebar on the media's page.",
+				Description: "The HTML code to embed this media.",
+				Deprecated:  "If you want to programmatically embed videos, follow the \"construct an embed code\" guide.",
+			},
+			"description": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				DiffSuppressFunc: suppressEquivalentDescription,
+				Description:      "The description for the media which usually appears near the top of the sidebar on the media's page. Wistia stores it as HTML, so differences in paragraph tags and whitespace are ignored. If it's not set, or set to an empty string, the description is left as it is in Wistia.",
 			},
 			"hashed_id": {
 				Type:        schema.TypeString,
@@ -110,41 +216,50 @@ func mediaResource() *schema.Resource {
 	}
 }
 
-func createMedia(d *schema.ResourceData, m interface{}) error {
+func createMedia(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	wc := m.(*wistia.Client)
-	media := mediaFromResource(d)
-	var err error
-	if filePath, ok := d.GetOk("file"); ok {
-		filePath := filePath.(string)
-		f, err := os.Open(filePath)
-		if err != nil {
-			return fmt.Errorf("couldn't open file '%s': %s", filePath, err)
-		}
-		defer f.Close()
-		media, err = wc.Media.CreateFromReader(context.Background(), media, f, path.Base(filePath))
-		if err != nil {
-			return fmt.Errorf("couldn't create media: %s", err)
+	claim, err := newUploadClaim()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	media, err := adoptUploadedMedia(ctx, wc, d, claim)
+	if err != nil {
+		return diag.Errorf("couldn't look for an earlier upload of the media: %s", err)
+	}
+	if media != nil {
+		log.Printf("[INFO] Adopting media %s, which was uploaded from the same file before", media.HashedId)
+	} else if media, err = uploadClaimedMedia(ctx, wc, d, claim); err != nil {
+		return diag.Errorf("couldn't create media: %s", err)
+	}
+
+	if _, claimedBy := parseHashMarker(media.Description); claimedBy == claim {
+		d.Set("upload_claim", claim)
+	}
+	applyMediaFieldsToResource(media, d)
+
+	if d.Get("wait_for_ready").(bool) {
+		ready, err := wc.Media.WaitForStatus(ctx, media.HashedId, wistia.MediaStatusReady)
+		if ready != nil {
+			applyMediaFieldsToResource(ready, d)
 		}
-	} else {
-		url := d.Get("url").(string)
-		media, err = wc.Media.CreateFromURL(context.Background(), media, url)
 		if err != nil {
-			return fmt.Errorf("couldn't create media: %s", err)
+			return diag.Errorf("media %s isn't ready: %s", media.HashedId, err)
 		}
 	}
 
-	log.Printf("[TRACE] Newly created media: %v", media)
-
-	applyMediaFieldsToResource(media, d)
-
 	return nil
 }
 
-func readMedia(d *schema.ResourceData, m interface{}) error {
+func readMedia(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	wc := m.(*wistia.Client)
-	media, err := wc.Media.Get(context.Background(), d.Id())
+	media, err := wc.Media.Get(ctx, d.Id())
+	if errors.Is(err, wistia.ErrNotFound) {
+		log.Printf("[WARN] Wistia media %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
 	if err != nil {
-		return fmt.Errorf("couldn't get media: %s", err)
+		return diag.Errorf("couldn't get media: %s", err)
 	}
 
 	log.Printf("[TRACE] Read media: %v", media)
@@ -154,26 +269,59 @@ func readMedia(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
-func updateMedia(d *schema.ResourceData, m interface{}) error {
+func updateMedia(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	wc := m.(*wistia.Client)
+	if d.Get("source_hash").(string) != "" && d.Get("upload_claim").(string) == "" {
+		// Media created before claims were recorded is claimed when it's next updated.
+		claim, err := newUploadClaim()
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		d.Set("upload_claim", claim)
+	}
 	media := mediaFromResource(d)
-	media, err := wc.Media.Update(context.Background(), media)
+	var diags diag.Diagnostics
+	if d.HasChange("project_id") {
+		originalId := d.Id()
+		warnings, err := moveMedia(ctx, wc, d, media)
+		if err != nil {
+			// Once the resource refers to a copy in the new project, project_id is already right.
+			if d.Id() == originalId {
+				oldProject, _ := d.GetChange("project_id")
+				d.Set("project_id", oldProject)
+			}
+			return diag.Errorf("couldn't move media %s: %s", originalId, err)
+		}
+		diags = append(diags, warnings...)
+	}
+	if mediaSourceChanged(d) {
+		if err := replaceMedia(ctx, wc, d, media); err != nil {
+			// The SDK saves the planned values even when an update fails, so the source has to be set back for the
+			// next plan to try the replacement again.
+			for _, key := range []string{"source_hash", "source_size", "source_modified", "url", "source_version"} {
+				old, _ := d.GetChange(key)
+				d.Set(key, old)
+			}
+			return append(diags, diag.Errorf("couldn't replace media %s: %s", media.HashedId, err)...)
+		}
+	}
+	media, err := wc.Media.Update(ctx, media)
 	if err != nil {
-		return fmt.Errorf("couldn't update media: %s", err)
+		return append(diags, diag.Errorf("couldn't update media: %s", err)...)
 	}
 
 	log.Printf("[TRACE] Read media: %v", media)
 
 	applyMediaFieldsToResource(media, d)
 
-	return nil
+	return diags
 }
 
-func deleteMedia(d *schema.ResourceData, m interface{}) error {
+func deleteMedia(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	wc := m.(*wistia.Client)
 	media := mediaFromResource(d)
-	if err := wc.Media.Delete(context.Background(), media); err != nil {
-		return fmt.Errorf("couldn't delete media: %s", err)
+	if err := wc.Media.Delete(ctx, media); err != nil {
+		return diag.Errorf("couldn't delete media: %s", err)
 	}
 
 	return nil
@@ -181,48 +329,565 @@ func deleteMedia(d *schema.ResourceData, m interface{}) error {
 
 // Private helpers
 
+// uploadMedia creates a new media from the resource's file or url.
+func uploadMedia(ctx context.Context, wc *wistia.Client, d *schema.ResourceData, media *wistia.Media) (*wistia.Media, error) {
+	filePath, ok := d.GetOk("file")
+	if !ok {
+		return wc.Media.CreateFromURL(ctx, media, d.Get("url").(string))
+	}
+
+	f, err := os.Open(filePath.(string))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't open file '%s': %s", filePath, err)
+	}
+	defer f.Close()
+	reportProgress := func(p wistia.UploadProgress) {
+		log.Printf("[INFO] Uploading %s: %s", filePath, formatUploadProgress(p))
+	}
+	return wc.Media.CreateFromReader(ctx, media, f, path.Base(filePath.(string)),
+		wistia.WithProgress(reportProgress, uploadProgressInterval))
+}
+
+// adoptUploadedMedia looks in the resource's project for a media with the same name that was uploaded from a file
+// with the same source_hash, e.g. by an apply that failed before the media was saved to state, and that no resource
+// has claimed yet. If it finds one, it claims it for the resource and returns it. It returns nil if there's none, or
+// the resource doesn't upload a file.
+func adoptUploadedMedia(ctx context.Context, wc *wistia.Client, d *schema.ResourceData, claim string) (*wistia.Media, error) {
+	hash := d.Get("source_hash").(string)
+	if hash == "" {
+		return nil, nil
+	}
+	opts := &wistia.MediaListOptions{
+		ProjectId: d.Get("project_id").(string),
+		Name:      d.Get("name").(string),
+	}
+	var candidates []wistia.Media
+	err := wc.Media.Each(ctx, opts, func(media *wistia.Media) error {
+		mediaHash, mediaClaim := parseHashMarker(media.Description)
+		if media.Status != wistia.MediaStatusFailed && mediaHash == hash && mediaClaim == "" {
+			candidates = append(candidates, *media)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range candidates {
+		media, err := claimMedia(ctx, wc, &candidates[i], claim)
+		if errors.Is(err, errAlreadyClaimed) {
+			log.Printf("[DEBUG] Media %s was claimed by another resource first", candidates[i].HashedId)
+			continue
+		}
+		if errors.Is(err, errNoHashMarker) {
+			return nil, fmt.Errorf("couldn't claim media %s: %s", candidates[i].HashedId, err)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return media, nil
+	}
+	return nil, nil
+}
+
+// uploadClaimedMedia uploads the resource's file or url and claims the new media for the resource. The upload is left
+// unclaimed until it has succeeded, so that if the response is lost, the next apply can adopt it. That leaves a short
+// window in which another resource can adopt it first, in which case the file is uploaded once more. If Wistia didn't
+// keep the marker in the description, the media is returned unclaimed, since it can't be adopted either.
+func uploadClaimedMedia(ctx context.Context, wc *wistia.Client, d *schema.ResourceData, claim string) (*wistia.Media, error) {
+	for attempt := 1; ; attempt++ {
+		media, err := uploadMedia(ctx, wc, d, mediaFromResource(d))
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("[TRACE] Newly created media: %v", media)
+		if d.Get("source_hash").(string) == "" {
+			return media, nil
+		}
+
+		// Until it's claimed, the media is tracked so that it's deleted if claiming fails.
+		applyMediaFieldsToResource(media, d)
+		claimed, err := claimMedia(ctx, wc, media, claim)
+		if err == nil {
+			return claimed, nil
+		}
+		if errors.Is(err, errNoHashMarker) {
+			log.Printf("[WARN] Couldn't mark media %s as managed, so it can't be adopted if this apply fails: %s", media.HashedId, err)
+			return media, nil
+		}
+		if !errors.Is(err, errAlreadyClaimed) {
+			return nil, fmt.Errorf("couldn't mark media %s as managed: %s", media.HashedId, err)
+		}
+		// The media belongs to the resource that adopted it, so it mustn't be tracked, and deleted, as this one's.
+		d.SetId("")
+		if attempt == 2 {
+			return nil, fmt.Errorf("media %s was adopted by another resource: %s", media.HashedId, err)
+		}
+		log.Printf("[WARN] Media %s was adopted by another resource; uploading the file again", media.HashedId)
+	}
+}
+
+var (
+	errAlreadyClaimed = errors.New("media is already claimed")
+	// errNoHashMarker means the media's description has no marker to record a claim in, e.g. because Wistia dropped it.
+	errNoHashMarker = errors.New("media's description has no source_hash marker")
+)
+
+// claimMedia records claim in the media's description. The API can't update the description conditionally, so the
+// media is read again before and after claiming it, to catch another resource claiming it at the same time.
+func claimMedia(ctx context.Context, wc *wistia.Client, media *wistia.Media, claim string) (*wistia.Media, error) {
+	current, err := wc.Media.Get(ctx, media.HashedId)
+	if err != nil {
+		return nil, err
+	}
+	hash, currentClaim := parseHashMarker(current.Description)
+	if hash == "" {
+		return nil, errNoHashMarker
+	}
+	if currentClaim != "" && currentClaim != claim {
+		return nil, errAlreadyClaimed
+	}
+
+	update := &wistia.Media{
+		Id:          current.Id,
+		Name:        current.Name,
+		Project:     current.Project,
+		Type:        current.Type,
+		Section:     current.Section,
+		Duration:    current.Duration,
+		Created:     current.Created,
+		Updated:     current.Updated,
+		Description: withHashMarker(current.Description, hash, claim),
+		HashedId:    current.HashedId,
+	}
+	if _, err := wc.Media.Update(ctx, update); err != nil {
+		return nil, err
+	}
+
+	claimed, err := wc.Media.Get(ctx, media.HashedId)
+	if err != nil {
+		return nil, err
+	}
+	claimedHash, claimedBy := parseHashMarker(claimed.Description)
+	if claimedHash == "" {
+		return nil, errNoHashMarker
+	}
+	if claimedBy != claim {
+		return nil, errAlreadyClaimed
+	}
+	return claimed, nil
+}
+
+func newUploadClaim() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("couldn't generate upload claim: %s", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// mediaSourceChanged reports whether the media's content has to be replaced. A source_hash that's only being
+// recorded for the first time, e.g. for media created by an older version of the provider, doesn't count.
+func mediaSourceChanged(d *schema.ResourceData) bool {
+	if d.HasChange("url") || d.HasChange("source_version") {
+		return true
+	}
+	oldHash, newHash := d.GetChange("source_hash")
+	return oldHash.(string) != "" && oldHash.(string) != newHash.(string)
+}
+
+// moveMedia moves the media to the resource's new project_id. If the API doesn't support moving it and
+// allow_copy_on_move is set, it copies the media there and deletes the original instead, and media is updated to
+// refer to the copy. An original that couldn't be deleted is reported as a warning, since the copy is already in use.
+func moveMedia(ctx context.Context, wc *wistia.Client, d *schema.ResourceData, media *wistia.Media) (diag.Diagnostics, error) {
+	projectId := d.Get("project_id").(string)
+	err := wc.Media.Move(ctx, projectId, media.HashedId)
+	if err == nil {
+		return nil, nil
+	}
+	unsupported, checkErr := moveUnsupported(ctx, wc, media.HashedId, projectId, err)
+	if checkErr != nil {
+		return nil, checkErr
+	}
+	if !unsupported {
+		return nil, err
+	}
+	if !d.Get("allow_copy_on_move").(bool) {
+		return nil, fmt.Errorf("Wistia can't move it to project %s (%s). Set allow_copy_on_move to copy it there and "+
+			"delete the original instead, which gives it a new hashed ID and loses its stats, captions and customizations", projectId, err)
+	}
+
+	log.Printf("[WARN] Couldn't move media %s (%s); copying it to project %s and deleting the original instead", media.HashedId, err, projectId)
+	original := &wistia.Media{HashedId: media.HashedId}
+	copied, err := wc.Media.Copy(ctx, original, projectId, "")
+	if err != nil {
+		return nil, fmt.Errorf("couldn't copy media to project %s: %s", projectId, err)
+	}
+	media.Id = copied.Id
+	media.HashedId = copied.HashedId
+	d.SetId(copied.HashedId)
+	d.Set("hashed_id", copied.HashedId)
+	d.Set("media_id", copied.Id)
+
+	if err := wc.Media.Delete(ctx, original); err != nil && !errors.Is(err, wistia.ErrNotFound) {
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Couldn't delete media %s after copying it", original.HashedId),
+			Detail:   fmt.Sprintf("The media was copied to %s in project %s, which this resource now manages, but the original is still in its old project and has to be deleted separately: %s", copied.HashedId, projectId, err),
+		}}, nil
+	}
+	return nil, nil
+}
+
+// moveUnsupported reports whether err, returned by Move, means the API doesn't offer moving medias rather than that
+// the move failed. Since a 404 can also mean the media or the project doesn't exist, both are checked in that case,
+// and an error is returned if either is missing.
+func moveUnsupported(ctx context.Context, wc *wistia.Client, mediaId, projectId string, err error) (bool, error) {
+	var apiErr *wistia.APIError
+	if !errors.As(err, &apiErr) {
+		return false, nil
+	}
+	switch apiErr.StatusCode {
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return true, nil
+	case http.StatusNotFound:
+		if _, err := wc.Media.Get(ctx, mediaId); err != nil {
+			return false, fmt.Errorf("couldn't get media %s: %s", mediaId, err)
+		}
+		if _, err := wc.Projects.Get(ctx, projectId); err != nil {
+			return false, fmt.Errorf("couldn't get project %s: %s", projectId, err)
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// customizeMediaMoveDiff plans hashed_id and media_id as unknown when a move may fall back to copying the media.
+func customizeMediaMoveDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" || !d.HasChange("project_id") || !d.Get("allow_copy_on_move").(bool) {
+		return nil
+	}
+	if err := d.SetNewComputed("hashed_id"); err != nil {
+		return err
+	}
+	return d.SetNewComputed("media_id")
+}
+
+// replaceMedia uploads the resource's new source as a separate media, waits until it's processed, and swaps it in.
+func replaceMedia(ctx context.Context, wc *wistia.Client, d *schema.ResourceData, media *wistia.Media) error {
+	replacement, err := uploadMedia(ctx, wc, d, &wistia.Media{Name: media.Name, Project: media.Project, Description: media.Description})
+	if err != nil {
+		return fmt.Errorf("couldn't upload replacement: %s", err)
+	}
+	log.Printf("[DEBUG] Uploaded %s to replace media %s", replacement.HashedId, media.HashedId)
+
+	if _, err = wc.Media.WaitForStatus(ctx, replacement.HashedId, wistia.MediaStatusReady); err == nil {
+		_, err = wc.Media.Replace(ctx, media, replacement.HashedId)
+	}
+	if err != nil {
+		if deleteErr := wc.Media.Delete(ctx, replacement); deleteErr != nil {
+			log.Printf("[WARN] Couldn't delete unused replacement media %s: %s", replacement.HashedId, deleteErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// Extensions of the files Wistia can process. Files with other extensions are accepted if their content looks like
+// one of mediaContentTypes.
+var mediaFileExtensions = map[string]bool{
+	".3gp": true, ".avi": true, ".flv": true, ".m2ts": true, ".m4v": true, ".mkv": true, ".mov": true, ".mp4": true,
+	".mpeg": true, ".mpg": true, ".mts": true, ".mxf": true, ".ogv": true, ".ts": true, ".webm": true, ".wmv": true,
+	".aac": true, ".aif": true, ".aiff": true, ".flac": true, ".m4a": true, ".mp3": true, ".ogg": true, ".wav": true,
+	".wma": true,
+	".bmp": true, ".gif": true, ".jpeg": true, ".jpg": true, ".png": true, ".tif": true, ".tiff": true, ".webp": true,
+	".doc": true, ".docx": true, ".pdf": true, ".ppt": true, ".pptx": true, ".swf": true, ".xls": true, ".xlsx": true,
+}
+
+var mediaContentTypes = []string{"video/", "audio/", "image/", "application/pdf", "application/ogg"}
+
+// validateMediaFile checks that file can be uploaded, so that a bad file fails the plan rather than the apply. A file
+// that hasn't changed since it was last hashed isn't checked again, and neither is a missing file whose path hasn't
+// changed, which customizeMediaDiff warns about.
+func validateMediaFile(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	filePath := d.Get("file").(string)
+	if !d.NewValueKnown("file") || filePath == "" {
+		return nil
+	}
+
+	info, err := os.Stat(filePath)
+	if os.IsNotExist(err) && d.Id() != "" && !d.HasChange("file") {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("can't read file '%s': %s", filePath, err)
+	}
+	if sourceFileUnchanged(d, info) {
+		return nil
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("'%s' isn't a regular file", filePath)
+	}
+	if info.Size() == 0 {
+		return fmt.Errorf("file '%s' is empty", filePath)
+	}
+	if max := int64(d.Get("max_file_size").(int)); max > 0 && info.Size() > max {
+		return fmt.Errorf("file '%s' is %s, more than max_file_size of %s", filePath, formatBytes(float64(info.Size())), formatBytes(float64(max)))
+	}
+
+	if mediaFileExtensions[strings.ToLower(filepath.Ext(filePath))] {
+		return nil
+	}
+	f, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("can't read file '%s': %s", filePath, err)
+	}
+	defer f.Close()
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return fmt.Errorf("can't read file '%s': %s", filePath, err)
+	}
+	contentType := http.DetectContentType(head[:n])
+	for _, prefix := range mediaContentTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return nil
+		}
+	}
+	return fmt.Errorf("file '%s' doesn't look like a video, audio, image or document file that Wistia can process (detected %s)", filePath, contentType)
+}
+
+// validateMediaURL checks that url can be fetched if check_url is set.
+func validateMediaURL(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	sourceUrl := d.Get("url").(string)
+	if !d.Get("check_url").(bool) || !d.NewValueKnown("url") || sourceUrl == "" || !d.HasChange("url") {
+		return nil
+	}
+	wc := m.(*wistia.Client)
+	if err := wc.Media.CheckSourceURL(ctx, sourceUrl); err != nil {
+		return fmt.Errorf("can't fetch url '%s': %s", sourceUrl, err)
+	}
+	return nil
+}
+
+func sourceSizeSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeInt,
+		Computed:    true,
+		Description: "The size of `file` in bytes when it was last hashed. `file` is only hashed again when its size or modification time changes.",
+	}
+}
+
+func sourceModifiedSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The modification time of `file` when it was last hashed.",
+	}
+}
+
+// customizeMediaDiff sets source_hash to the SHA-256 of file. Since files can be many gigabytes, the file is only
+// read when its path, size or modification time differ from those recorded with the last hash. The file is only
+// required when the resource is created or its path changes, so that existing resources can be planned on machines
+// that don't have it.
+func customizeMediaDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if !d.NewValueKnown("file") {
+		for _, key := range []string{"source_hash", "source_size", "source_modified"} {
+			if err := d.SetNewComputed(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	hash, size, modified := "", int64(0), ""
+	if filePath := d.Get("file").(string); filePath != "" {
+		info, err := os.Stat(filePath)
+		if os.IsNotExist(err) && d.Id() != "" && !d.HasChange("file") {
+			log.Printf("[WARN] File '%s' doesn't exist; keeping the source_hash recorded when it was last hashed", filePath)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("couldn't hash file '%s': %s", filePath, err)
+		}
+		if sourceFileUnchanged(d, info) {
+			return nil
+		}
+		if hash, err = fileSHA256(filePath); err != nil {
+			return fmt.Errorf("couldn't hash file '%s': %s", filePath, err)
+		}
+		// A file that was only touched, e.g. by checking it out again, keeps its recorded size and modification
+		// time, so that it doesn't show up in the plan. It's hashed again until its contents change.
+		if hash == d.Get("source_hash").(string) && !d.HasChange("file") {
+			return nil
+		}
+		size, modified = info.Size(), formatModTime(info)
+	}
+
+	values := map[string]interface{}{"source_hash": hash, "source_size": int(size), "source_modified": modified}
+	for key, value := range values {
+		if d.Get(key) != value {
+			if err := d.SetNew(key, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// sourceFileUnchanged reports whether file is the same file, with the same size and modification time, as when
+// source_hash was computed.
+func sourceFileUnchanged(d *schema.ResourceDiff, info os.FileInfo) bool {
+	return !d.HasChange("file") && d.Get("source_hash").(string) != "" &&
+		int64(d.Get("source_size").(int)) == info.Size() && d.Get("source_modified").(string) == formatModTime(info)
+}
+
+func formatModTime(info os.FileInfo) string {
+	return info.ModTime().UTC().Format(time.RFC3339Nano)
+}
+
+func fileSHA256(filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+const uploadProgressInterval = 15 * time.Second
+
+func formatUploadProgress(p wistia.UploadProgress) string {
+	s := formatBytes(float64(p.BytesSent))
+	if p.TotalBytes > 0 {
+		s += fmt.Sprintf(" of %s (%.0f%%)", formatBytes(float64(p.TotalBytes)), 100*float64(p.BytesSent)/float64(p.TotalBytes))
+	}
+	s += fmt.Sprintf(", %s/s", formatBytes(p.Rate))
+	if p.ETA > 0 {
+		s += fmt.Sprintf(", about %s remaining", p.ETA.Round(time.Second))
+	}
+	return s
+}
+
+func formatBytes(n float64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+	i := 0
+	for ; n >= 1024 && i < len(units)-1; i++ {
+		n /= 1024
+	}
+	return fmt.Sprintf("%.1f %s", n, units[i])
+}
+
 func applyMediaFieldsToResource(m *wistia.Media, d *schema.ResourceData) {
 	d.SetId(m.HashedId)
 	d.Set("media_id", m.Id)
 	d.Set("name", m.Name)
+	if m.Project.HashedId != "" {
+		d.Set("project_id", m.Project.HashedId)
+	}
 	d.Set("type", m.Type)
+	d.Set("status", m.Status)
 	d.Set("section", m.Section)
-	//d.Set("thumbnail", m.Thumbnail)
+	d.Set("thumbnail", flattenThumbnail(m.Thumbnail))
 	d.Set("duration", m.Duration)
 	d.Set("created", m.Created)
 	d.Set("updated", m.Updated)
-	//d.Set("assets", m.Assets)
-	//d.Set("embed_code", m.EmbedCode)
-	d.Set("description", m.Description)
+	d.Set("assets", flattenAssets(m.Assets))
+	d.Set("embed_code", m.EmbedCode)
+	d.Set("description", stripHashMarker(m.Description))
 	d.Set("hashed_id", m.HashedId)
 }
 
 func mediaFromResource(d *schema.ResourceData) *wistia.Media {
-	//thumbMap := d.Get("thumbnail").(map[string]interface{})
-	//thumbnail := wistia.Thumbnail{}
-	//if url, ok := thumbMap["url"]; ok {
-	//	thumbnail.URL = url.(string)
-	//}
-	//if width, ok := thumbMap["width"]; ok {
-	//	thumbnail.Width = width.(int)
-	//}
-	//if height, ok := thumbMap["height"]; ok {
-	//	thumbnail.Height = height.(int)
-	//}
-
 	return &wistia.Media{
-		Id:      d.Get("media_id").(int),
-		Name:    d.Get("name").(string),
-		Project: wistia.Project{HashedId: d.Get("project_id").(string)},
-		Type:    d.Get("type").(string),
-		Section: d.Get("section").(string),
-		//Thumbnail:   thumbnail,
-		Duration: d.Get("duration").(float64),
-		Created:  d.Get("created").(string),
-		Updated:  d.Get("updated").(string),
-		//Assets:      d.Get("assets").([]wistia.Asset),
-		//EmbedCode:   d.Get("embed_code").(string),
-		Description: d.Get("description").(string),
+		Id:          d.Get("media_id").(int),
+		Name:        d.Get("name").(string),
+		Project:     wistia.Project{HashedId: d.Get("project_id").(string)},
+		Type:        d.Get("type").(string),
+		Section:     d.Get("section").(string),
+		Duration:    d.Get("duration").(float64),
+		Created:     d.Get("created").(string),
+		Updated:     d.Get("updated").(string),
+		Description: withHashMarker(d.Get("description").(string), d.Get("source_hash").(string), d.Get("upload_claim").(string)),
 		HashedId:    d.Get("hashed_id").(string),
 	}
 }
+
+// Media uploaded from a file records the file's source_hash in its description, in an HTML comment that isn't shown
+// to viewers, so that adoptUploadedMedia can recognize it later. Once a resource manages the media, the comment also
+// holds the resource's upload_claim.
+var hashMarkerPattern = regexp.MustCompile(`\s*<!-- terraform-provider-wistia:sha256=([0-9a-f]{64})(?:;claim=([0-9a-f]+))? -->`)
+
+func withHashMarker(description, hash, claim string) string {
+	description = stripHashMarker(description)
+	if hash == "" {
+		return description
+	}
+	marker := fmt.Sprintf("<!-- terraform-provider-wistia:sha256=%s -->", hash)
+	if claim != "" {
+		marker = fmt.Sprintf("<!-- terraform-provider-wistia:sha256=%s;claim=%s -->", hash, claim)
+	}
+	if description == "" {
+		return marker
+	}
+	return description + "\n" + marker
+}
+
+// suppressEquivalentDescription ignores the differences between a description as configured and as Wistia returns
+// it, which wraps it in a paragraph and may reflow it.
+func suppressEquivalentDescription(_, old, new string, _ *schema.ResourceData) bool {
+	return normalizeDescription(old) == normalizeDescription(new)
+}
+
+var whitespacePattern = regexp.MustCompile(`\s+`)
+
+func normalizeDescription(description string) string {
+	description = strings.TrimSpace(stripHashMarker(description))
+	if strings.HasPrefix(description, "<p>") && strings.HasSuffix(description, "</p>") &&
+		strings.Count(description, "<p>") == 1 {
+		description = strings.TrimSpace(description[len("<p>") : len(description)-len("</p>")])
+	}
+	return whitespacePattern.ReplaceAllString(description, " ")
+}
+
+// parseHashMarker returns the source_hash and claim recorded in the description, if any.
+func parseHashMarker(description string) (hash, claim string) {
+	if match := hashMarkerPattern.FindStringSubmatch(description); match != nil {
+		return match[1], match[2]
+	}
+	return "", ""
+}
+
+func stripHashMarker(description string) string {
+	return hashMarkerPattern.ReplaceAllString(description, "")
+}
+
+func flattenThumbnail(t *wistia.Thumbnail) []interface{} {
+	if t == nil {
+		return nil
+	}
+	return []interface{}{map[string]interface{}{
+		"url":    t.URL,
+		"width":  t.Width,
+		"height": t.Height,
+	}}
+}
+
+func flattenAssets(assets []wistia.Asset) []interface{} {
+	flattened := make([]interface{}, len(assets))
+	for i, a := range assets {
+		flattened[i] = map[string]interface{}{
+			"url":          a.URL,
+			"type":         a.Type,
+			"content_type": a.ContentType,
+			"file_size":    a.FileSize,
+			"width":        a.Width,
+			"height":       a.Height,
+		}
+	}
+	return flattened
+}
@@ -0,0 +1,153 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/wistia/terraform-provider-wistia/internal/wistia"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+func mediaAssetFileResource() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: createMediaAssetFile,
+		ReadContext:   readMediaAssetFile,
+		DeleteContext: deleteMediaAssetFile,
+		Description:   "Downloads an asset of a Wistia media, such as the original file or one of its renditions, to a local file. The file is downloaded again if it's missing or its contents have changed. Destroying this resource deletes the local file.",
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(6 * time.Hour),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"media_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The hashed ID of the media.",
+			},
+			"asset_type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "OriginalFile",
+				Description: "The type of asset to download, such as `OriginalFile`, `HdMp4VideoFile` or `Mp4AudioFile`. See the `assets` of `wistia_media` for what's available. Defaults to `OriginalFile`.",
+			},
+			"path": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The path of the local file to write the asset to. Missing parent directories are created.",
+			},
+			"size": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The size of the file in bytes.",
+			},
+			"modified": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The modification time of the file when it was last hashed. The file is only hashed again when its size or modification time changes.",
+			},
+			"sha256": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The SHA-256 of the file's contents.",
+			},
+		},
+	}
+}
+
+func createMediaAssetFile(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	wc := m.(*wistia.Client)
+	media := &wistia.Media{HashedId: d.Get("media_id").(string)}
+	assetType := d.Get("asset_type").(string)
+	filePath := d.Get("path").(string)
+
+	// Download to a temporary file next to the destination, so that a failed download never leaves a partial file
+	// at the destination.
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return diag.Errorf("couldn't create directory for '%s': %s", filePath, err)
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(filePath), filepath.Base(filePath)+".*.tmp")
+	if err != nil {
+		return diag.Errorf("couldn't create temporary file for '%s': %s", filePath, err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	h := sha256.New()
+	size, err := wc.Media.DownloadAsset(ctx, media, assetType, io.MultiWriter(tmp, h))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return diag.Errorf("couldn't download %s asset of media %s: %s", assetType, media.HashedId, err)
+	}
+	if err := os.Rename(tmp.Name(), filePath); err != nil {
+		return diag.Errorf("couldn't move downloaded asset to '%s': %s", filePath, err)
+	}
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return diag.Errorf("couldn't read asset file: %s", err)
+	}
+
+	log.Printf("[DEBUG] Downloaded %s asset of media %s to %s (%d bytes)", assetType, media.HashedId, filePath, size)
+
+	d.SetId(filePath)
+	d.Set("size", size)
+	d.Set("sha256", hex.EncodeToString(h.Sum(nil)))
+	d.Set("modified", formatModTime(info))
+
+	return nil
+}
+
+func readMediaAssetFile(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	filePath := d.Get("path").(string)
+	info, err := os.Stat(filePath)
+	if os.IsNotExist(err) {
+		log.Printf("[WARN] Asset file %s not found, removing from state", filePath)
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("couldn't read asset file: %s", err)
+	}
+
+	if info.Size() != int64(d.Get("size").(int)) {
+		log.Printf("[WARN] Asset file %s has changed size, removing from state", filePath)
+		d.SetId("")
+		return nil
+	}
+	// Assets can be many gigabytes, so the file is only hashed again if it may have been written to.
+	modified := formatModTime(info)
+	if modified == d.Get("modified").(string) {
+		return nil
+	}
+	hash, err := fileSHA256(filePath)
+	if err != nil {
+		return diag.Errorf("couldn't hash asset file: %s", err)
+	}
+	if hash != d.Get("sha256").(string) {
+		log.Printf("[WARN] Asset file %s has changed, removing from state", filePath)
+		d.SetId("")
+		return nil
+	}
+	d.Set("modified", modified)
+
+	return nil
+}
+
+func deleteMediaAssetFile(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	filePath := d.Get("path").(string)
+	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+		return diag.Errorf("couldn't delete asset file: %s", err)
+	}
+
+	return nil
+}
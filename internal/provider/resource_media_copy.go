@@ -0,0 +1,122 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/wistia/terraform-provider-wistia/internal/wistia"
+	"log"
+)
+
+func mediaCopyResource() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: createMediaCopy,
+		ReadContext:   readMediaCopy,
+		DeleteContext: deleteMediaCopy,
+		Description:   "A copy of an existing Wistia media, made without uploading the original again. Destroying this resource deletes the copy. See the [API documentation](https://wistia.com/support/developers/data-api#medias_copy) for more details.",
+
+		Schema: map[string]*schema.Schema{
+			"source_media_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The hashed ID of the media to copy.",
+			},
+			"project_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The hashed ID of the project the copy is placed in. Defaults to the project of the source media.",
+			},
+			"owner": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The email address of the account user who will own the copy.",
+			},
+			"hashed_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "A unique alphanumeric identifier for the copy.",
+			},
+			"media_id": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "A unique numeric identifier for the copy within the system.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The display name of the copy.",
+			},
+			"type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "A string representing what type of media the copy is.",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Post upload processing status of the copy. There are four statuses: queued, processing, ready, and failed.",
+			},
+		},
+	}
+}
+
+func createMediaCopy(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	wc := m.(*wistia.Client)
+	source := &wistia.Media{HashedId: d.Get("source_media_id").(string)}
+	media, err := wc.Media.Copy(ctx, source, d.Get("project_id").(string), d.Get("owner").(string))
+	if err != nil {
+		return diag.Errorf("couldn't copy media %s: %s", source.HashedId, err)
+	}
+
+	log.Printf("[TRACE] Newly copied media: %v", media)
+
+	applyMediaCopyFieldsToResource(media, d)
+
+	return nil
+}
+
+func readMediaCopy(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	wc := m.(*wistia.Client)
+	media, err := wc.Media.Get(ctx, d.Id())
+	if errors.Is(err, wistia.ErrNotFound) {
+		log.Printf("[WARN] Wistia media copy %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("couldn't get media copy: %s", err)
+	}
+
+	applyMediaCopyFieldsToResource(media, d)
+
+	return nil
+}
+
+func deleteMediaCopy(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	wc := m.(*wistia.Client)
+	err := wc.Media.Delete(ctx, &wistia.Media{HashedId: d.Id()})
+	if err != nil && !errors.Is(err, wistia.ErrNotFound) {
+		return diag.Errorf("couldn't delete media copy: %s", err)
+	}
+
+	return nil
+}
+
+// Private helpers
+
+func applyMediaCopyFieldsToResource(m *wistia.Media, d *schema.ResourceData) {
+	d.SetId(m.HashedId)
+	d.Set("hashed_id", m.HashedId)
+	d.Set("media_id", m.Id)
+	d.Set("name", m.Name)
+	d.Set("type", m.Type)
+	d.Set("status", m.Status)
+	if m.Project.HashedId != "" {
+		d.Set("project_id", m.Project.HashedId)
+	}
+}
@@ -0,0 +1,250 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/wistia/terraform-provider-wistia/internal/wistia"
+	"log"
+	"time"
+)
+
+var thumbnailSources = []string{"file", "url", "image_media_id"}
+
+func mediaThumbnailResource() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: createMediaThumbnail,
+		ReadContext:   readMediaThumbnail,
+		UpdateContext: updateMediaThumbnail,
+		DeleteContext: deleteMediaThumbnail,
+		Description:   "The custom thumbnail of a Wistia media. The image is uploaded as an image media, or taken from an existing one, and set as the still that's shown before the media starts playing. If the thumbnail is changed outside of Terraform, it's set again on the next apply. Destroying this resource deletes any image media it uploaded, but Wistia keeps showing the last thumbnail that was set.",
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+		},
+		CustomizeDiff: customdiff.All(customizeMediaDiff, customizeMediaThumbnailDiff),
+
+		Schema: map[string]*schema.Schema{
+			"media_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The hashed ID of the media whose thumbnail is set.",
+			},
+			"file": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: thumbnailSources,
+				Description:  "A path to an image on disk that will be uploaded to Wistia and used as the thumbnail.",
+			},
+			"url": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: thumbnailSources,
+				Description:  "A URL to an image that will be uploaded to Wistia and used as the thumbnail.",
+			},
+			"image_media_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: thumbnailSources,
+				Description:  "The hashed ID of an existing image media to use as the thumbnail.",
+			},
+			"project_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The hashed ID of the project that an image from `file` or `url` is uploaded to. Defaults to the project of the media.",
+			},
+			"source_hash": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The SHA-256 of `file`, computed when planning.",
+			},
+			"source_size":     sourceSizeSchema(),
+			"source_modified": sourceModifiedSchema(),
+			"uploaded_media_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The hashed ID of the image media uploaded from `file` or `url`. Empty when `image_media_id` is used.",
+			},
+			"thumbnail_url": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The URL of the media's thumbnail after it was set. It's compared with the media's current thumbnail to detect changes made outside of Terraform.",
+			},
+		},
+	}
+}
+
+func createMediaThumbnail(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	wc := m.(*wistia.Client)
+	media := &wistia.Media{HashedId: d.Get("media_id").(string)}
+
+	if _, ok := d.GetOk("project_id"); !ok {
+		current, err := wc.Media.Get(ctx, media.HashedId)
+		if err != nil {
+			return diag.Errorf("couldn't get media %s: %s", media.HashedId, err)
+		}
+		d.Set("project_id", current.Project.HashedId)
+	}
+
+	imageId, err := thumbnailImage(ctx, wc, d, false)
+	if err != nil {
+		return diag.Errorf("couldn't set thumbnail of media %s: %s", media.HashedId, err)
+	}
+	// The resource is tracked as soon as an image has been uploaded, so that it's deleted on destroy even if setting
+	// the thumbnail fails.
+	d.SetId(media.HashedId)
+
+	if err := setMediaThumbnail(ctx, wc, d, media, imageId); err != nil {
+		return diag.Errorf("couldn't set thumbnail of media %s: %s", media.HashedId, err)
+	}
+
+	return nil
+}
+
+func readMediaThumbnail(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	wc := m.(*wistia.Client)
+	media, err := wc.Media.Get(ctx, d.Id())
+	if errors.Is(err, wistia.ErrNotFound) {
+		log.Printf("[WARN] Wistia media %s not found, removing thumbnail from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("couldn't get media: %s", err)
+	}
+
+	currentUrl := ""
+	if media.Thumbnail != nil {
+		currentUrl = media.Thumbnail.URL
+	}
+	if currentUrl != d.Get("thumbnail_url").(string) {
+		// Clearing thumbnail_url makes the next plan set the thumbnail again.
+		log.Printf("[WARN] Thumbnail of media %s has changed to %s outside of Terraform", d.Id(), currentUrl)
+		d.Set("thumbnail_url", "")
+	}
+
+	return nil
+}
+
+func updateMediaThumbnail(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	wc := m.(*wistia.Client)
+	media := &wistia.Media{HashedId: d.Id()}
+
+	sourceChanged := thumbnailSourceChanged(d)
+	previousUpload := d.Get("uploaded_media_id").(string)
+
+	imageId, err := thumbnailImage(ctx, wc, d, !sourceChanged)
+	if err != nil {
+		return diag.Errorf("couldn't set thumbnail of media %s: %s", media.HashedId, err)
+	}
+	if err := setMediaThumbnail(ctx, wc, d, media, imageId); err != nil {
+		return diag.Errorf("couldn't set thumbnail of media %s: %s", media.HashedId, err)
+	}
+
+	if sourceChanged && previousUpload != "" {
+		deleteThumbnailImage(ctx, wc, previousUpload)
+		if d.Get("uploaded_media_id").(string) == previousUpload {
+			d.Set("uploaded_media_id", "")
+		}
+	}
+
+	return nil
+}
+
+func deleteMediaThumbnail(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	wc := m.(*wistia.Client)
+	if imageId := d.Get("uploaded_media_id").(string); imageId != "" {
+		err := wc.Media.Delete(ctx, &wistia.Media{HashedId: imageId})
+		if err != nil && !errors.Is(err, wistia.ErrNotFound) {
+			return diag.Errorf("couldn't delete thumbnail image media: %s", err)
+		}
+	}
+
+	return nil
+}
+
+// Private helpers
+
+// customizeMediaThumbnailDiff plans to set the thumbnail again when its source has changed, or when
+// readMediaThumbnail found it was changed outside of Terraform.
+func customizeMediaThumbnailDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	if d.HasChange("url") || d.HasChange("image_media_id") || d.HasChange("source_hash") {
+		if err := d.SetNewComputed("uploaded_media_id"); err != nil {
+			return err
+		}
+		return d.SetNewComputed("thumbnail_url")
+	}
+	if d.Get("thumbnail_url").(string) == "" {
+		return d.SetNewComputed("thumbnail_url")
+	}
+	return nil
+}
+
+// thumbnailSourceChanged reports whether a different image has to be used for the thumbnail.
+func thumbnailSourceChanged(d *schema.ResourceData) bool {
+	if d.HasChange("url") || d.HasChange("image_media_id") {
+		return true
+	}
+	oldHash, newHash := d.GetChange("source_hash")
+	return oldHash.(string) != newHash.(string)
+}
+
+// thumbnailImage returns the hashed ID of the image media to use as the thumbnail, uploading file or url unless
+// reuseUpload is set and it has been uploaded already.
+func thumbnailImage(ctx context.Context, wc *wistia.Client, d *schema.ResourceData, reuseUpload bool) (string, error) {
+	if imageId, ok := d.GetOk("image_media_id"); ok {
+		return imageId.(string), nil
+	}
+	if imageId := d.Get("uploaded_media_id").(string); reuseUpload && imageId != "" {
+		return imageId, nil
+	}
+
+	image := &wistia.Media{
+		Name:    fmt.Sprintf("Thumbnail for %s", d.Get("media_id").(string)),
+		Project: wistia.Project{HashedId: d.Get("project_id").(string)},
+	}
+	image, err := uploadMedia(ctx, wc, d, image)
+	if err != nil {
+		return "", fmt.Errorf("couldn't upload image: %s", err)
+	}
+	log.Printf("[DEBUG] Uploaded thumbnail image %s for media %s", image.HashedId, d.Get("media_id").(string))
+	d.Set("uploaded_media_id", image.HashedId)
+
+	return image.HashedId, nil
+}
+
+// setMediaThumbnail waits until the image media has been processed and sets it as the media's thumbnail.
+func setMediaThumbnail(ctx context.Context, wc *wistia.Client, d *schema.ResourceData, media *wistia.Media, imageId string) error {
+	if _, err := wc.Media.WaitForStatus(ctx, imageId, wistia.MediaStatusReady); err != nil {
+		return fmt.Errorf("image media %s isn't ready: %s", imageId, err)
+	}
+	media, err := wc.Media.SetThumbnail(ctx, media, imageId)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[TRACE] Media with new thumbnail: %v", media)
+
+	if media.Thumbnail != nil {
+		d.Set("thumbnail_url", media.Thumbnail.URL)
+	} else {
+		d.Set("thumbnail_url", "")
+	}
+	return nil
+}
+
+func deleteThumbnailImage(ctx context.Context, wc *wistia.Client, imageId string) {
+	err := wc.Media.Delete(ctx, &wistia.Media{HashedId: imageId})
+	if err != nil && !errors.Is(err, wistia.ErrNotFound) {
+		log.Printf("[WARN] Couldn't delete unused thumbnail image media %s: %s", imageId, err)
+	}
+}
@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/wistia/terraform-provider-wistia/internal/wistia"
@@ -92,6 +93,11 @@ func createProject(d *schema.ResourceData, m interface{}) error {
 func readProject(d *schema.ResourceData, m interface{}) error {
 	wc := m.(*wistia.Client)
 	p, err := wc.Projects.Get(context.Background(), d.Id())
+	if errors.Is(err, wistia.ErrNotFound) {
+		log.Printf("[WARN] Wistia project %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("couldn't get Wistia project: %s", err)
 	}
@@ -0,0 +1,193 @@
+package wistia
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DefaultBulkUploadConcurrency is how many files CreateMany uploads at once unless told otherwise.
+const DefaultBulkUploadConcurrency = 4
+
+// ErrUploadSkipped is the error of a BulkUploadResult for a file that wasn't uploaded because CreateMany was canceled
+// or stopped after another file failed.
+var ErrUploadSkipped = errors.New("upload skipped")
+
+// A BulkUpload is one file for CreateMany to upload.
+type BulkUpload struct {
+	Media *Media
+	// Open is called when a worker starts on the file, so that only as many files are open as are being uploaded.
+	// Readers that can seek can be retried if the upload fails part way.
+	Open     func() (io.ReadCloser, error)
+	Filename string
+	Options  []UploadOption
+}
+
+// BulkUploadFile returns a BulkUpload of the file at filePath.
+func BulkUploadFile(m *Media, filePath string, opts ...UploadOption) BulkUpload {
+	return BulkUpload{
+		Media: m,
+		Open: func() (io.ReadCloser, error) {
+			return os.Open(filePath)
+		},
+		Filename: filepath.Base(filePath),
+		Options:  opts,
+	}
+}
+
+type BulkUploadOptions struct {
+	// Concurrency is the number of files uploaded at once. It defaults to DefaultBulkUploadConcurrency.
+	Concurrency int
+	// StopOnError skips the files that haven't been started yet once an upload fails. Uploads in progress finish.
+	StopOnError bool
+	// OnResult, if set, is called as each upload finishes. Calls are never concurrent.
+	OnResult func(BulkUploadResult)
+}
+
+type BulkUploadResult struct {
+	// Index is the position of the file in the slice passed to CreateMany.
+	Index    int
+	Filename string
+	// Media is the created media, or nil if Err is set.
+	Media    *Media
+	Err      error
+	Duration time.Duration
+}
+
+// BulkUploadReport is the outcome of CreateMany.
+type BulkUploadReport struct {
+	// Results holds one result per file, in the order the files were given.
+	Results []BulkUploadResult
+	Elapsed time.Duration
+}
+
+// Counts returns how many files were uploaded, failed, and were skipped.
+func (r *BulkUploadReport) Counts() (succeeded, failed, skipped int) {
+	for _, result := range r.Results {
+		switch {
+		case result.Err == nil:
+			succeeded++
+		case errors.Is(result.Err, ErrUploadSkipped):
+			skipped++
+		default:
+			failed++
+		}
+	}
+	return succeeded, failed, skipped
+}
+
+// Err returns an error describing the first file that failed or was skipped, or nil if every file was uploaded.
+func (r *BulkUploadReport) Err() error {
+	succeeded, _, _ := r.Counts()
+	if succeeded == len(r.Results) {
+		return nil
+	}
+	for _, result := range r.Results {
+		if result.Err != nil {
+			return fmt.Errorf("%d of %d uploads didn't succeed, the first was %s: %w", len(r.Results)-succeeded,
+				len(r.Results), result.Filename, result.Err)
+		}
+	}
+	return nil
+}
+
+// Summary describes the report in one line, e.g. for logging.
+func (r *BulkUploadReport) Summary() string {
+	succeeded, failed, skipped := r.Counts()
+	return fmt.Sprintf("uploaded %d of %d files in %s (%d failed, %d skipped)", succeeded, len(r.Results),
+		r.Elapsed.Round(time.Second), failed, skipped)
+}
+
+// CreateMany uploads files with CreateFromReader using a bounded pool of workers, which share the client's retry
+// policy, rate limiter and upload bandwidth limit. It always returns a report with a result for every file; files
+// that weren't started when ctx was done are reported as skipped.
+func (mp *MediaProvider) CreateMany(ctx context.Context, uploads []BulkUpload, opts *BulkUploadOptions) *BulkUploadReport {
+	if opts == nil {
+		opts = &BulkUploadOptions{}
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBulkUploadConcurrency
+	}
+
+	// Canceling dispatch stops new uploads from starting, but lets those in progress finish.
+	dispatch, stopDispatch := context.WithCancel(ctx)
+	defer stopDispatch()
+
+	start := time.Now()
+	report := &BulkUploadReport{Results: make([]BulkUploadResult, len(uploads))}
+	var resultMu sync.Mutex
+	finish := func(result BulkUploadResult) {
+		resultMu.Lock()
+		defer resultMu.Unlock()
+		report.Results[result.Index] = result
+		if opts.OnResult != nil {
+			opts.OnResult(result)
+		}
+		if result.Err != nil && opts.StopOnError {
+			stopDispatch()
+		}
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency && i < len(uploads); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				finish(mp.createOne(ctx, index, uploads[index]))
+			}
+		}()
+	}
+
+	for index := range uploads {
+		// Checking dispatch first means no more files are started once it's done, even if a worker is free.
+		if dispatch.Err() == nil {
+			select {
+			case jobs <- index:
+				continue
+			case <-dispatch.Done():
+			}
+		}
+		reason := "an earlier upload failed"
+		if ctx.Err() != nil {
+			reason = ctx.Err().Error()
+		}
+		finish(BulkUploadResult{
+			Index:    index,
+			Filename: uploads[index].Filename,
+			Err:      fmt.Errorf("%w: %s", ErrUploadSkipped, reason),
+		})
+	}
+	close(jobs)
+	wg.Wait()
+
+	report.Elapsed = time.Since(start)
+	return report
+}
+
+func (mp *MediaProvider) createOne(ctx context.Context, index int, upload BulkUpload) (result BulkUploadResult) {
+	result = BulkUploadResult{Index: index, Filename: upload.Filename}
+	start := time.Now()
+	defer func() { result.Duration = time.Since(start) }()
+
+	r, err := upload.Open()
+	if err != nil {
+		result.Err = fmt.Errorf("couldn't open %s: %s", upload.Filename, err)
+		return result
+	}
+	defer r.Close()
+
+	m := upload.Media
+	if m == nil {
+		m = &Media{}
+	}
+	result.Media, result.Err = mp.CreateFromReader(ctx, m, r, upload.Filename, upload.Options...)
+	return result
+}
@@ -0,0 +1,101 @@
+package wistia
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+type CaptionsProvider provider
+
+type Caption struct {
+	// Language is the 3-character ISO-639-2 code of the captions' language, e.g. "eng".
+	Language    string `json:"language"`
+	EnglishName string `json:"english_name,omitempty"`
+	NativeName  string `json:"native_name,omitempty"`
+	// Text is the captions in SRT format.
+	Text string `json:"text"`
+}
+
+type captionRequest struct {
+	Language    string `json:"language,omitempty"`
+	CaptionFile string `json:"caption_file"`
+}
+
+func (cp *CaptionsProvider) List(ctx context.Context, mediaId string) ([]Caption, error) {
+	var captions []Caption
+	url := cp.client.APIBaseEndpoint + fmt.Sprintf("medias/%s/captions.json", mediaId)
+	_, err := cp.client.request(ctx, http.MethodGet, url, nil, &captions)
+	if err != nil {
+		return nil, err
+	}
+	return captions, nil
+}
+
+func (cp *CaptionsProvider) Get(ctx context.Context, mediaId, language string) (*Caption, error) {
+	caption := &Caption{}
+	url := cp.client.APIBaseEndpoint + fmt.Sprintf("medias/%s/captions/%s.json", mediaId, language)
+	_, err := cp.client.request(ctx, http.MethodGet, url, nil, caption)
+	if err != nil {
+		return nil, err
+	}
+	return caption, nil
+}
+
+// Create adds captions in a language the media doesn't have captions for yet. The API responds with ErrBadRequest
+// if it does.
+func (cp *CaptionsProvider) Create(ctx context.Context, mediaId string, c *Caption) error {
+	url := cp.client.APIBaseEndpoint + fmt.Sprintf("medias/%s/captions.json", mediaId)
+	_, err := cp.client.request(ctx, http.MethodPost, url, &captionRequest{Language: c.Language, CaptionFile: c.Text}, nil)
+	return err
+}
+
+func (cp *CaptionsProvider) Update(ctx context.Context, mediaId string, c *Caption) error {
+	url := cp.client.APIBaseEndpoint + fmt.Sprintf("medias/%s/captions/%s.json", mediaId, c.Language)
+	_, err := cp.client.request(ctx, http.MethodPut, url, &captionRequest{CaptionFile: c.Text}, nil)
+	return err
+}
+
+func (cp *CaptionsProvider) Delete(ctx context.Context, mediaId, language string) error {
+	url := cp.client.APIBaseEndpoint + fmt.Sprintf("medias/%s/captions/%s.json", mediaId, language)
+	_, err := cp.client.request(ctx, http.MethodDelete, url, nil, nil)
+	return err
+}
+
+type purchaseCaptionsRequest struct {
+	Automated bool `json:"automated"`
+}
+
+// Purchase orders captions for the media from Wistia, either machine-generated if automated is set, or transcribed by
+// a person. The captions are added to the media once they're done; use WaitForCaptions to wait for them. Purchases
+// are billed to the account, so the request is only retried if it's rate limited.
+func (cp *CaptionsProvider) Purchase(ctx context.Context, mediaId string, automated bool) error {
+	url := cp.client.APIBaseEndpoint + fmt.Sprintf("medias/%s/captions/purchase.json", mediaId)
+	_, err := cp.client.request(onlyThrottledRetries(ctx), http.MethodPost, url, &purchaseCaptionsRequest{Automated: automated}, nil)
+	return err
+}
+
+// WaitForCaptions polls the media until it has captions in at least one language, or ctx is done. It polls every 10
+// seconds at first and backs off to every 2 minutes, since ordered captions can take hours to arrive.
+func (cp *CaptionsProvider) WaitForCaptions(ctx context.Context, mediaId string) ([]Caption, error) {
+	delay := 10 * time.Second
+	for {
+		captions, err := cp.List(ctx, mediaId)
+		if err != nil {
+			return nil, err
+		}
+		if len(captions) > 0 {
+			return captions, nil
+		}
+
+		log.Printf("[DEBUG] Media %s has no captions yet; waiting", mediaId)
+		if err := sleep(ctx, delay); err != nil {
+			return nil, fmt.Errorf("stopped waiting for captions for media %s: %w", mediaId, err)
+		}
+		if delay = delay * 3 / 2; delay > 2*time.Minute {
+			delay = 2 * time.Minute
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package wistia
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors that an *APIError matches with errors.Is, based on its status code.
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrServerError  = errors.New("server error")
+)
+
+// APIError is returned when the Wistia API responds with a 4xx or 5xx status.
+type APIError struct {
+	StatusCode int
+	// Message is the error message decoded from the response body, if there was one.
+	Message string
+	// Body is the raw response body.
+	Body      []byte
+	Method    string
+	Endpoint  string
+	RequestID string
+}
+
+func (e *APIError) Error() string {
+	message := e.Message
+	if message == "" {
+		message = strings.TrimSpace(string(e.Body))
+	}
+	s := fmt.Sprintf("the Wistia API responded to %s %s with status %d", e.Method, e.Endpoint, e.StatusCode)
+	if message != "" {
+		s += ": " + redactPatterns(message)
+	}
+	if e.RequestID != "" {
+		s += fmt.Sprintf(" (request ID %s)", e.RequestID)
+	}
+	return s
+}
+
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServerError:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
+
+var requestIDHeaders = []string{"X-Request-Id", "X-Amzn-Requestid", "X-Amz-Cf-Id"}
+
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Message:    decodeErrorMessage(body),
+		Body:       body,
+	}
+	if resp.Request != nil {
+		apiErr.Method = resp.Request.Method
+		apiErr.Endpoint = resp.Request.URL.Scheme + "://" + resp.Request.URL.Host + resp.Request.URL.Path
+	}
+	for _, header := range requestIDHeaders {
+		if id := resp.Header.Get(header); id != "" {
+			apiErr.RequestID = id
+			break
+		}
+	}
+	return apiErr
+}
+
+// decodeErrorMessage extracts the message from JSON error bodies like {"error": "..."}.
+func decodeErrorMessage(body []byte) string {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return ""
+	}
+	for _, key := range []string{"error", "message", "errors"} {
+		switch value := fields[key].(type) {
+		case string:
+			return value
+		case []interface{}:
+			var messages []string
+			for _, v := range value {
+				if s, ok := v.(string); ok {
+					messages = append(messages, s)
+				}
+			}
+			if len(messages) > 0 {
+				return strings.Join(messages, "; ")
+			}
+		}
+	}
+	return ""
+}
+
+// checkResponse returns an *APIError if resp has an error status. The response body is consumed in that case.
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %s", err)
+	}
+	return newAPIError(resp, body)
+}
+
+// Stages of a file upload, as reported by UploadError.
+const (
+	UploadStageFormField = "form field"
+	UploadStageFileCopy  = "file copy"
+	UploadStageHTTP      = "HTTP"
+)
+
+// UploadError is returned by CreateFromReader and names the stage of the upload that failed. The underlying error,
+// which may be an *APIError, is available through errors.Is and errors.As.
+type UploadError struct {
+	Stage string
+	Err   error
+}
+
+func (e *UploadError) Error() string {
+	return fmt.Sprintf("upload failed during %s: %s", e.Stage, e.Err)
+}
+
+func (e *UploadError) Unwrap() error {
+	return e.Err
+}
@@ -3,18 +3,31 @@ package wistia
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"log"
 	"mime/multipart"
 	"net/http"
 	"net/url"
-	"os"
 	"strings"
+	"time"
 )
 
 type MediaProvider provider
 
+// Processing statuses of a media.
+const (
+	MediaStatusQueued     = "queued"
+	MediaStatusProcessing = "processing"
+	MediaStatusReady      = "ready"
+	MediaStatusFailed     = "failed"
+)
+
+// ErrMediaFailed is returned by WaitForStatus when processing ends with the failed status.
+var ErrMediaFailed = errors.New("media processing failed")
+
 type Thumbnail struct {
 	URL    string `json:"url"`
 	Width  int    `json:"width"`
@@ -31,89 +44,166 @@ type Asset struct {
 }
 
 type Media struct {
-	Id      int     `json:"id"`
-	Name    string  `json:"name"`
-	Project Project `json:"project"`
-	Type    string  `json:"type"`
-	Status  string  `json:"status"`
-	Section string  `json:"section"`
-	//Thumbnail   Thumbnail `json:"thumbnail"`
-	Duration float64 `json:"duration"`
-	Created  string  `json:"created"`
-	Updated  string  `json:"updated"`
-	//Assets      []Asset   `json:"assets"`
-	//EmbedCode   string    `json:"embedCode"`
-	Description string `json:"description"`
-	HashedId    string `json:"hashed_id"`
-}
-
-func (mp *MediaProvider) CreateFromReader(ctx context.Context, m *Media, r io.Reader, filename string) (*Media, error) {
+	Id          int        `json:"id"`
+	Name        string     `json:"name"`
+	Project     Project    `json:"project"`
+	Type        string     `json:"type"`
+	Status      string     `json:"status"`
+	Section     string     `json:"section"`
+	Thumbnail   *Thumbnail `json:"thumbnail,omitempty"`
+	Duration    float64    `json:"duration"`
+	Created     string     `json:"created"`
+	Updated     string     `json:"updated"`
+	Assets      []Asset    `json:"assets,omitempty"`
+	EmbedCode   string     `json:"embedCode,omitempty"`
+	Description string     `json:"description"`
+	HashedId    string     `json:"hashed_id"`
+}
+
+func (mp *MediaProvider) CreateFromReader(ctx context.Context, m *Media, r io.Reader, filename string, opts ...UploadOption) (*Media, error) {
 	req, err := mp.client.newRequest(ctx, http.MethodPost, mp.client.UploadBaseEndpoint)
 	if err != nil {
 		return nil, err
 	}
 
-	pipeReader, pipeWriter := io.Pipe()
-	multipartWriter := multipart.NewWriter(pipeWriter)
+	o := &uploadOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	source := r
+	if mp.client.UploadBandwidthLimiter != nil {
+		source = &throttledReader{ctx: ctx, r: source, limiter: mp.client.UploadBandwidthLimiter}
+	}
+	var tracker *progressTracker
+	completed := false
+	if o.progress != nil {
+		size := o.size
+		if size == 0 {
+			size = readerSize(r)
+		}
+		tracker = newProgressTracker(size)
+		source = tracker.reader(source)
+		interval := o.progressInterval
+		if interval <= 0 {
+			interval = 10 * time.Second
+		}
+		stop, stopped := make(chan struct{}), make(chan struct{})
+		go func() {
+			defer close(stopped)
+			tracker.report(o.progress, interval, stop)
+		}()
+		defer func() {
+			close(stop)
+			<-stopped
+			if completed {
+				o.progress(tracker.progress())
+			}
+		}()
+	}
 
-	req.Header.Add("Content-Type", multipartWriter.FormDataContentType())
-	req.Body = pipeReader
+	// The body is streamed through a pipe so that large files never have to be held in memory. Errors on the writing
+	// side reach the HTTP client through CloseWithError. If the reader can seek, the body can be rebuilt from the same
+	// starting offset, which allows the request to be retried.
+	contentType := multipart.NewWriter(nil)
+	var writerDone chan struct{}
+	var currentBody *io.PipeReader
+	openBody := func() io.ReadCloser {
+		pipeReader, pipeWriter := io.Pipe()
+		multipartWriter := multipart.NewWriter(pipeWriter)
+		_ = multipartWriter.SetBoundary(contentType.Boundary())
+		done := make(chan struct{})
 
-	go func() {
-		defer func() { _ = pipeWriter.Close() }()
-		defer func() { _ = multipartWriter.Close() }()
+		go func() {
+			defer close(done)
+			_ = pipeWriter.CloseWithError(writeMediaForm(ctx, multipartWriter, m, source, filename))
+		}()
 
-		if err := multipartWriter.WriteField("name", m.Name); err != nil {
-			_, _ = fmt.Fprintf(os.Stderr, "error creating name field: %s", err)
-			return
-		}
-		if err := multipartWriter.WriteField("description", m.Description); err != nil {
-			_, _ = fmt.Fprintf(os.Stderr, "error creating description field: %s", err)
-			return
-		}
-		if err := multipartWriter.WriteField("project_id", m.Project.HashedId); err != nil {
-			_, _ = fmt.Fprintf(os.Stderr, "error creating project_id field: %s", err)
-			return
-		}
-		if err := multipartWriter.WriteField("access_token", mp.client.accessToken); err != nil {
-			_, _ = fmt.Fprintf(os.Stderr, "error creating access token field: %s", err)
-			return
-		}
-		formFile, err := multipartWriter.CreateFormFile("file", filename)
-		if err != nil {
-			_, _ = fmt.Fprintf(os.Stderr, "error creating form file: %s", err)
-			return
-		}
-		if _, err := io.Copy(formFile, r); err != nil {
-			_, _ = fmt.Fprintf(os.Stderr, "error during copy: %s", err)
-			return
+		writerDone, currentBody = done, pipeReader
+		return pipeReader
+	}
+
+	req.Header.Add("Content-Type", contentType.FormDataContentType())
+	req.Body = openBody()
+	if seeker, ok := r.(io.Seeker); ok {
+		if start, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+			req.GetBody = func() (io.ReadCloser, error) {
+				// Stop the previous writer before rewinding the reader it's copying from.
+				_ = currentBody.Close()
+				<-writerDone
+				if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+					return nil, err
+				}
+				if tracker != nil {
+					tracker.reset()
+				}
+				return openBody(), nil
+			}
 		}
-	}()
+	}
 
-	resp, err := mp.client.httpClient.Do(req)
+	resp, err := mp.client.do(mp.client.uploadHTTPClient(), req)
 	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode >= http.StatusBadRequest {
-		body, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			return nil, err
+		var uploadErr *UploadError
+		if errors.As(err, &uploadErr) && ctx.Err() == nil {
+			return nil, uploadErr
 		}
-		return nil, fmt.Errorf("the Wistia API responded with an error while creating the media: %s", body)
+		return nil, &UploadError{Stage: UploadStageHTTP, Err: err}
 	}
 	defer func() {
 		_, _ = io.Copy(ioutil.Discard, resp.Body)
 		_ = resp.Body.Close()
 	}()
+	if err := checkResponse(resp); err != nil {
+		return nil, &UploadError{Stage: UploadStageHTTP, Err: err}
+	}
 
 	createdMedia := &Media{}
 	err = json.NewDecoder(resp.Body).Decode(createdMedia)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode JSON from response body: %s", err)
+		return nil, &UploadError{Stage: UploadStageHTTP, Err: fmt.Errorf("failed to decode JSON from response body: %s", err)}
 	}
+	completed = true
 	return createdMedia, nil
 }
 
+// writeMediaForm writes the multipart form for uploading m, with the file's contents read from r.
+func writeMediaForm(ctx context.Context, w *multipart.Writer, m *Media, r io.Reader, filename string) error {
+	fields := []struct{ name, value string }{
+		{"name", m.Name},
+		{"description", m.Description},
+		{"project_id", m.Project.HashedId},
+	}
+	for _, field := range fields {
+		if err := w.WriteField(field.name, field.value); err != nil {
+			return &UploadError{Stage: UploadStageFormField, Err: fmt.Errorf("couldn't write %s: %s", field.name, err)}
+		}
+	}
+	formFile, err := w.CreateFormFile("file", filename)
+	if err != nil {
+		return &UploadError{Stage: UploadStageFormField, Err: fmt.Errorf("couldn't create file field: %s", err)}
+	}
+	if _, err := io.Copy(formFile, &contextReader{ctx: ctx, r: r}); err != nil {
+		return &UploadError{Stage: UploadStageFileCopy, Err: err}
+	}
+	if err := w.Close(); err != nil {
+		return &UploadError{Stage: UploadStageFormField, Err: fmt.Errorf("couldn't finish form: %s", err)}
+	}
+	return nil
+}
+
+// contextReader stops reading once its context is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (cr *contextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.r.Read(p)
+}
+
 func (mp *MediaProvider) CreateFromURL(ctx context.Context, m *Media, sourceAssetUrl string) (*Media, error) {
 	req, err := mp.client.newRequest(ctx, http.MethodPost, mp.client.UploadBaseEndpoint)
 	if err != nil {
@@ -123,14 +213,19 @@ func (mp *MediaProvider) CreateFromURL(ctx context.Context, m *Media, sourceAsse
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
 	values := url.Values{
-		"access_token": {mp.client.accessToken},
-		"name":         {m.Name},
-		"project_id":   {m.Project.HashedId},
-		"url":          {sourceAssetUrl},
+		"name":        {m.Name},
+		"description": {m.Description},
+		"project_id":  {m.Project.HashedId},
+		"url":         {sourceAssetUrl},
+	}
+	payload := values.Encode()
+	log.Printf("[TRACE] Upload request body: %s", mp.client.redact.String(payload))
+	req.Body = ioutil.NopCloser(strings.NewReader(payload))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(strings.NewReader(payload)), nil
 	}
-	req.Body = ioutil.NopCloser(strings.NewReader(values.Encode()))
 
-	resp, err := mp.client.httpClient.Do(req)
+	resp, err := mp.client.do(mp.client.uploadHTTPClient(), req)
 	if err != nil {
 		return nil, err
 	}
@@ -138,12 +233,8 @@ func (mp *MediaProvider) CreateFromURL(ctx context.Context, m *Media, sourceAsse
 		_, _ = io.Copy(ioutil.Discard, resp.Body)
 		_ = resp.Body.Close()
 	}()
-	if resp.StatusCode >= http.StatusBadRequest {
-		body, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			return nil, err
-		}
-		return nil, fmt.Errorf("the Wistia API responded with error %d while creating the media: %s", resp.StatusCode, body)
+	if err := checkResponse(resp); err != nil {
+		return nil, err
 	}
 
 	createdMedia := &Media{}
@@ -164,6 +255,315 @@ func (mp *MediaProvider) Get(ctx context.Context, id string) (*Media, error) {
 	return media, nil
 }
 
+type copyMediaRequest struct {
+	ProjectId string `json:"project_id,omitempty"`
+	Owner     string `json:"owner,omitempty"`
+}
+
+// Copy copies the media into the project with the given hashed ID, or into the media's own project if projectId is
+// empty. owner is the email address of the account user who will own the copy; if it's empty, the API decides.
+func (mp *MediaProvider) Copy(ctx context.Context, m *Media, projectId, owner string) (*Media, error) {
+	apiUrl := mp.client.APIBaseEndpoint + fmt.Sprintf("medias/%s/copy.json", m.HashedId)
+	copiedMedia := &Media{}
+	_, err := mp.client.request(ctx, http.MethodPost, apiUrl, &copyMediaRequest{ProjectId: projectId, Owner: owner}, copiedMedia)
+	if err != nil {
+		return nil, err
+	}
+	return copiedMedia, nil
+}
+
+type moveMediasRequest struct {
+	HashedIds []string `json:"hashed_ids"`
+	ProjectId string   `json:"project_id"`
+}
+
+// Move moves the medias with the given hashed IDs into the project with the hashed ID projectId. Unlike Copy, the
+// medias keep their hashed IDs, embed codes, stats and customizations.
+func (mp *MediaProvider) Move(ctx context.Context, projectId string, hashedIds ...string) error {
+	apiUrl := mp.client.APIBaseEndpoint + "medias/move.json"
+	_, err := mp.client.request(ctx, http.MethodPut, apiUrl, &moveMediasRequest{HashedIds: hashedIds, ProjectId: projectId}, nil)
+	return err
+}
+
+type swapMediaRequest struct {
+	ReplacementMediaId string `json:"replacement_media_id"`
+}
+
+// Replace swaps the content of the media for that of the media with the hashed ID replacementId, using the API's
+// media swap. The media keeps its hashed ID, embed codes, stats and customizations; the replacement media is consumed.
+func (mp *MediaProvider) Replace(ctx context.Context, m *Media, replacementId string) (*Media, error) {
+	apiUrl := mp.client.APIBaseEndpoint + fmt.Sprintf("medias/%s/swap.json", m.HashedId)
+	replacedMedia := &Media{}
+	_, err := mp.client.request(ctx, http.MethodPut, apiUrl, &swapMediaRequest{ReplacementMediaId: replacementId}, replacedMedia)
+	if err != nil {
+		return nil, err
+	}
+	return replacedMedia, nil
+}
+
+type setThumbnailRequest struct {
+	NewStillMediaId string `json:"new_still_media_id"`
+}
+
+// SetThumbnail makes the image media with the hashed ID imageId the still that's shown before the media starts
+// playing.
+func (mp *MediaProvider) SetThumbnail(ctx context.Context, m *Media, imageId string) (*Media, error) {
+	apiUrl := mp.client.APIBaseEndpoint + fmt.Sprintf("medias/%s.json", m.HashedId)
+	updatedMedia := &Media{}
+	_, err := mp.client.request(ctx, http.MethodPut, apiUrl, &setThumbnailRequest{NewStillMediaId: imageId}, updatedMedia)
+	if err != nil {
+		return nil, err
+	}
+	return updatedMedia, nil
+}
+
+// WaitForStatus polls the media until it reaches the given status, processing fails, or ctx is done. It polls every
+// 2 seconds at first and backs off to every 30 seconds. The last media that was read is returned along with any error.
+func (mp *MediaProvider) WaitForStatus(ctx context.Context, id string, status string) (*Media, error) {
+	delay := 2 * time.Second
+	for {
+		media, err := mp.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if media.Status == status {
+			return media, nil
+		}
+		if media.Status == MediaStatusFailed {
+			return media, fmt.Errorf("media %s: %w", id, ErrMediaFailed)
+		}
+
+		log.Printf("[DEBUG] Media %s has status %s; waiting for %s", id, media.Status, status)
+		if err := sleep(ctx, delay); err != nil {
+			return media, fmt.Errorf("stopped waiting for media %s to become %s, last status was %s: %w", id, status, media.Status, err)
+		}
+		if delay = delay * 3 / 2; delay > 30*time.Second {
+			delay = 30 * time.Second
+		}
+	}
+}
+
+// DownloadAsset writes the media's asset of the given type, such as OriginalFile or HdMp4VideoFile, to w. If m has no
+// assets, the media is fetched first. Interrupted downloads are resumed where they left off, and the number of bytes
+// written is checked against the asset's size. An error writing to w is returned as it is, without resuming.
+func (mp *MediaProvider) DownloadAsset(ctx context.Context, m *Media, assetType string, w io.Writer) (int64, error) {
+	if m.Assets == nil {
+		var err error
+		if m, err = mp.Get(ctx, m.HashedId); err != nil {
+			return 0, err
+		}
+	}
+	var asset *Asset
+	for i := range m.Assets {
+		if m.Assets[i].Type == assetType {
+			asset = &m.Assets[i]
+			break
+		}
+	}
+	if asset == nil {
+		return 0, fmt.Errorf("media %s has no %s asset", m.HashedId, assetType)
+	}
+
+	var written int64
+	for retry := 1; ; retry++ {
+		n, err := mp.downloadFrom(ctx, asset.URL, written, w)
+		written += n
+		if err == nil {
+			break
+		}
+		var apiErr *APIError
+		var writeErr *downloadWriteError
+		if ctx.Err() != nil || errors.As(err, &apiErr) || errors.As(err, &writeErr) || errors.Is(err, errCantResume) ||
+			retry > mp.client.RetryPolicy.MaxRetries {
+			return written, err
+		}
+		delay := mp.client.RetryPolicy.backoff(retry)
+		log.Printf("[DEBUG] Download of %s asset of media %s interrupted after %d bytes; resuming in %s: %s",
+			assetType, m.HashedId, written, delay, err)
+		if err := sleep(ctx, delay); err != nil {
+			return written, err
+		}
+	}
+
+	if asset.FileSize > 0 && written != int64(asset.FileSize) {
+		return written, fmt.Errorf("downloaded %d bytes of the %s asset of media %s, but it has %d", written, assetType, m.HashedId, asset.FileSize)
+	}
+	return written, nil
+}
+
+var errCantResume = errors.New("the server doesn't support resuming downloads")
+
+// downloadWriteError is an error writing a download to its destination, such as a full disk, which downloading the
+// rest of the file again wouldn't fix.
+type downloadWriteError struct {
+	err error
+}
+
+func (e *downloadWriteError) Error() string {
+	return e.err.Error()
+}
+
+func (e *downloadWriteError) Unwrap() error {
+	return e.err
+}
+
+// downloadWriter records the errors of the writer a download is copied to, to tell them apart from read errors.
+type downloadWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (dw *downloadWriter) Write(p []byte) (int, error) {
+	n, err := dw.w.Write(p)
+	if err == nil && n < len(p) {
+		err = io.ErrShortWrite
+	}
+	if err != nil {
+		dw.err = &downloadWriteError{err}
+	}
+	return n, err
+}
+
+// downloadFrom copies the file at assetUrl, starting at offset, to w. Asset URLs point at Wistia's CDN rather than
+// the API, so no credentials are sent. Downloads can take as long as uploads, so they use the upload HTTP client.
+func (mp *MediaProvider) downloadFrom(ctx context.Context, assetUrl string, offset int64, w io.Writer) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, assetUrl, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", defaultUserAgent)
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := mp.client.do(mp.client.uploadHTTPClient(), req)
+	if err != nil {
+		return 0, err
+	}
+	// The body isn't drained, so that a failed download doesn't fetch the rest of the file.
+	defer func() { _ = resp.Body.Close() }()
+	if err := checkResponse(resp); err != nil {
+		return 0, err
+	}
+	if offset > 0 && resp.StatusCode != http.StatusPartialContent {
+		return 0, errCantResume
+	}
+	dw := &downloadWriter{w: w}
+	n, err := io.Copy(dw, resp.Body)
+	if dw.err != nil {
+		return n, dw.err
+	}
+	return n, err
+}
+
+// CheckSourceURL makes sure sourceUrl can be fetched before it's passed to CreateFromURL, by sending it a HEAD
+// request. Servers that don't support HEAD are given the benefit of the doubt.
+func (mp *MediaProvider) CheckSourceURL(ctx context.Context, sourceUrl string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, sourceUrl, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", defaultUserAgent)
+
+	resp, err := mp.client.do(mp.client.uploadHTTPClient(), req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented {
+		return nil
+	}
+	return checkResponse(resp)
+}
+
+// Values for MediaListOptions.SortBy.
+const (
+	MediaSortByName    = "name"
+	MediaSortByCreated = "created"
+	MediaSortByUpdated = "updated"
+)
+
+// MediaListOptions filters and sorts medias. Zero values don't filter, and use the API's default order.
+type MediaListOptions struct {
+	ListOptions
+
+	// ProjectId is the hashed ID of the project the medias belong to.
+	ProjectId string
+	Name      string
+	// Type is one of Video, Audio, Image, PdfDocument, MicrosoftOfficeDocument, Swf, or UnknownType.
+	Type      string
+	HashedIds []string
+
+	SortBy     string
+	Descending bool
+}
+
+func (o *MediaListOptions) query() url.Values {
+	q := url.Values{}
+	if o == nil {
+		return q
+	}
+	if o.ProjectId != "" {
+		q.Set("project_id", o.ProjectId)
+	}
+	if o.Name != "" {
+		q.Set("name", o.Name)
+	}
+	if o.Type != "" {
+		q.Set("type", o.Type)
+	}
+	for _, id := range o.HashedIds {
+		q.Add("hashed_ids[]", id)
+	}
+	if o.SortBy != "" {
+		q.Set("sort_by", o.SortBy)
+		if o.Descending {
+			q.Set("sort_direction", "0")
+		} else {
+			q.Set("sort_direction", "1")
+		}
+	}
+	return q
+}
+
+// Iterate walks the medias in the account that match opts.
+func (mp *MediaProvider) Iterate(ctx context.Context, opts *MediaListOptions) *Iterator {
+	var listOpts *ListOptions
+	if opts != nil {
+		listOpts = &opts.ListOptions
+	}
+	return mp.client.iterate(ctx, mp.client.APIBaseEndpoint+"medias.json", opts.query(), listOpts)
+}
+
+// Each calls fn for every media that matches opts, stopping at the first error.
+func (mp *MediaProvider) Each(ctx context.Context, opts *MediaListOptions, fn func(*Media) error) error {
+	it := mp.Iterate(ctx, opts)
+	defer it.Close()
+	for it.Next() {
+		media := &Media{}
+		if err := it.Decode(media); err != nil {
+			return fmt.Errorf("failed to decode media: %s", err)
+		}
+		if err := fn(media); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+// List returns all medias that match opts, across all pages.
+func (mp *MediaProvider) List(ctx context.Context, opts *MediaListOptions) ([]Media, error) {
+	var medias []Media
+	err := mp.Each(ctx, opts, func(m *Media) error {
+		medias = append(medias, *m)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return medias, nil
+}
+
 func (mp *MediaProvider) Update(ctx context.Context, m *Media) (*Media, error) {
 	apiUrl := mp.client.APIBaseEndpoint + fmt.Sprintf("medias/%s.json", m.HashedId)
 	updatedMedia := &Media{}
@@ -0,0 +1,125 @@
+package wistia
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const maxPerPage = 100
+
+// ListOptions controls how collection endpoints are paged.
+type ListOptions struct {
+	// Page is the first page to fetch, starting at 1. Defaults to 1.
+	Page int
+	// PerPage is the number of items fetched per request. Defaults to and is capped at 100.
+	PerPage int
+}
+
+// Iterator walks a paginated collection endpoint item by item. The next page is fetched in the background while the
+// current one is being consumed. Close must be called if the iterator isn't drained.
+//
+//	it := client.Projects.Iterate(ctx, nil)
+//	defer it.Close()
+//	for it.Next() {
+//		var p wistia.Project
+//		if err := it.Decode(&p); err != nil { ... }
+//	}
+//	if err := it.Err(); err != nil { ... }
+type Iterator struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	pages   chan page
+	items   []json.RawMessage
+	current json.RawMessage
+	err     error
+}
+
+type page struct {
+	items []json.RawMessage
+	err   error
+}
+
+func (c *Client) iterate(ctx context.Context, endpoint string, query url.Values, opts *ListOptions) *Iterator {
+	first, perPage := 1, maxPerPage
+	if opts != nil {
+		if opts.Page > 0 {
+			first = opts.Page
+		}
+		if opts.PerPage > 0 && opts.PerPage < maxPerPage {
+			perPage = opts.PerPage
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	it := &Iterator{ctx: ctx, cancel: cancel, pages: make(chan page, 1)}
+	go it.fetch(c, endpoint, query, first, perPage)
+	return it
+}
+
+func (it *Iterator) fetch(c *Client, endpoint string, query url.Values, first, perPage int) {
+	defer close(it.pages)
+	for n := first; ; n++ {
+		q := url.Values{}
+		for k, v := range query {
+			q[k] = v
+		}
+		q.Set("page", strconv.Itoa(n))
+		q.Set("per_page", strconv.Itoa(perPage))
+
+		var items []json.RawMessage
+		_, err := c.request(it.ctx, http.MethodGet, endpoint+"?"+q.Encode(), nil, &items)
+		select {
+		case it.pages <- page{items: items, err: err}:
+		case <-it.ctx.Done():
+			return
+		}
+		if err != nil || len(items) < perPage {
+			return
+		}
+	}
+}
+
+// Next advances to the next item. It returns false when there are no more items or an error occurred.
+func (it *Iterator) Next() bool {
+	for len(it.items) == 0 {
+		if it.err != nil {
+			return false
+		}
+		select {
+		case p, ok := <-it.pages:
+			if !ok {
+				it.cancel()
+				return false
+			}
+			if p.err != nil {
+				it.err = p.err
+				it.cancel()
+				return false
+			}
+			it.items = p.items
+		case <-it.ctx.Done():
+			it.err = it.ctx.Err()
+			return false
+		}
+	}
+	it.current, it.items = it.items[0], it.items[1:]
+	return true
+}
+
+// Decode unmarshals the current item into v.
+func (it *Iterator) Decode(v interface{}) error {
+	return json.Unmarshal(it.current, v)
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// Close stops fetching pages.
+func (it *Iterator) Close() {
+	it.cancel()
+}
@@ -0,0 +1,125 @@
+package wistia
+
+import (
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// UploadProgress describes how far along a file upload is.
+type UploadProgress struct {
+	BytesSent int64
+	// TotalBytes is the size of the file, or 0 if it isn't known.
+	TotalBytes int64
+	// Rate is the average throughput in bytes per second since the upload (or its latest retry) started.
+	Rate float64
+	// ETA is the estimated time remaining, or 0 if it can't be estimated.
+	ETA time.Duration
+}
+
+// An UploadOption configures a single call to CreateFromReader.
+type UploadOption func(*uploadOptions)
+
+type uploadOptions struct {
+	progress         func(UploadProgress)
+	progressInterval time.Duration
+	size             int64
+}
+
+// WithProgress calls fn with the upload's progress once per interval while the file is being sent, and once more
+// when the upload has completed.
+func WithProgress(fn func(UploadProgress), interval time.Duration) UploadOption {
+	return func(o *uploadOptions) {
+		o.progress = fn
+		o.progressInterval = interval
+	}
+}
+
+// WithSize sets the number of bytes that will be read from the reader, for progress reporting. It's only needed if
+// the reader can't seek, since the size is determined automatically otherwise.
+func WithSize(size int64) UploadOption {
+	return func(o *uploadOptions) {
+		o.size = size
+	}
+}
+
+// readerSize returns the number of bytes left in r if it can seek, leaving its offset unchanged.
+func readerSize(r io.Reader) int64 {
+	seeker, ok := r.(io.Seeker)
+	if !ok {
+		return 0
+	}
+	current, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0
+	}
+	end, err := seeker.Seek(0, io.SeekEnd)
+	if _, seekErr := seeker.Seek(current, io.SeekStart); err != nil || seekErr != nil {
+		return 0
+	}
+	return end - current
+}
+
+// progressTracker counts the bytes read through it. It's shared by the upload's writer goroutine and whoever reports
+// progress, so the counters are accessed atomically.
+type progressTracker struct {
+	sent  int64
+	start int64 // UnixNano
+	total int64
+}
+
+func newProgressTracker(total int64) *progressTracker {
+	t := &progressTracker{total: total}
+	t.reset()
+	return t
+}
+
+// reset starts counting from zero, which happens when the upload is retried.
+func (t *progressTracker) reset() {
+	atomic.StoreInt64(&t.sent, 0)
+	atomic.StoreInt64(&t.start, time.Now().UnixNano())
+}
+
+func (t *progressTracker) reader(r io.Reader) io.Reader {
+	return &countingReader{r: r, count: &t.sent}
+}
+
+func (t *progressTracker) progress() UploadProgress {
+	p := UploadProgress{
+		BytesSent:  atomic.LoadInt64(&t.sent),
+		TotalBytes: t.total,
+	}
+	elapsed := time.Since(time.Unix(0, atomic.LoadInt64(&t.start))).Seconds()
+	if elapsed > 0 {
+		p.Rate = float64(p.BytesSent) / elapsed
+	}
+	if p.Rate > 0 && p.TotalBytes > p.BytesSent {
+		p.ETA = time.Duration(float64(p.TotalBytes-p.BytesSent) / p.Rate * float64(time.Second))
+	}
+	return p
+}
+
+// report calls fn every interval until stop is closed.
+func (t *progressTracker) report(fn func(UploadProgress), interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			fn(t.progress())
+		}
+	}
+}
+
+type countingReader struct {
+	r     io.Reader
+	count *int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	atomic.AddInt64(cr.count, int64(n))
+	return n, err
+}
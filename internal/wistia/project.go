@@ -2,6 +2,7 @@ package wistia
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 )
@@ -23,6 +24,23 @@ type Project struct {
 	PublicId                     string `json:"public_id,omitempty"`
 }
 
+// UnmarshalJSON accepts the project's hashed ID both as hashedId, which the projects endpoints use, and as hashed_id,
+// which is used for the project embedded in a media.
+func (p *Project) UnmarshalJSON(data []byte) error {
+	type project Project
+	aux := struct {
+		*project
+		HashedIdSnakeCase string `json:"hashed_id"` // XXX: Workaround for API inconsistency
+	}{project: (*project)(p)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if p.HashedId == "" {
+		p.HashedId = aux.HashedIdSnakeCase
+	}
+	return nil
+}
+
 func (pp *ProjectsProvider) Create(ctx context.Context, p *Project) (*Project, error) {
 	createdProject := &Project{}
 	url := pp.client.APIBaseEndpoint + "projects.json"
@@ -46,6 +64,42 @@ func (pp *ProjectsProvider) Get(ctx context.Context, id string) (*Project, error
 	return project, nil
 }
 
+// Iterate walks all projects in the account.
+func (pp *ProjectsProvider) Iterate(ctx context.Context, opts *ListOptions) *Iterator {
+	return pp.client.iterate(ctx, pp.client.APIBaseEndpoint+"projects.json", nil, opts)
+}
+
+// Each calls fn for every project in the account, stopping at the first error.
+func (pp *ProjectsProvider) Each(ctx context.Context, opts *ListOptions, fn func(*Project) error) error {
+	it := pp.Iterate(ctx, opts)
+	defer it.Close()
+	for it.Next() {
+		project := &Project{}
+		if err := it.Decode(project); err != nil {
+			return fmt.Errorf("failed to decode project: %s", err)
+		}
+		// XXX: Workaround for API bug
+		project.AnonymousCanUpload = project.AnonymousCanUploadOldStyle
+		project.AnonymousCanDownload = project.AnonymousCanDownloadOldStyle
+		if err := fn(project); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+func (pp *ProjectsProvider) List(ctx context.Context, opts *ListOptions) ([]Project, error) {
+	var projects []Project
+	err := pp.Each(ctx, opts, func(p *Project) error {
+		projects = append(projects, *p)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
 func (pp *ProjectsProvider) Update(ctx context.Context, p *Project) (*Project, error) {
 	updatedProject := &Project{}
 	url := pp.client.APIBaseEndpoint + fmt.Sprintf("projects/%s.json", p.HashedId)
@@ -0,0 +1,140 @@
+package wistia
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket shared by every request a Client makes to the API and upload endpoints. When the API
+// responds with 429, the limiter pauses for the Retry-After period and halves its rate, then recovers gradually as
+// requests succeed. A Client also uses a RateLimiter to cap upload bandwidth, with one token per byte.
+type RateLimiter struct {
+	mu          sync.Mutex
+	limit       float64 // configured tokens per second
+	rate        float64 // current tokens per second, lowered after a 429
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+func NewRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		limit:  requestsPerSecond,
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a request may be sent or ctx is done.
+func (rl *RateLimiter) Wait(ctx context.Context) error {
+	return rl.WaitN(ctx, 1)
+}
+
+// WaitN blocks until n tokens are available or ctx is done. n is capped at the limiter's burst.
+func (rl *RateLimiter) WaitN(ctx context.Context, n int) error {
+	tokens := float64(n)
+	if tokens > rl.burst {
+		tokens = rl.burst
+	}
+	for {
+		rl.mu.Lock()
+		now := time.Now()
+		rl.refill(now)
+		var delay time.Duration
+		if now.Before(rl.pausedUntil) {
+			delay = rl.pausedUntil.Sub(now)
+		} else if rl.tokens >= tokens {
+			rl.tokens -= tokens
+			rl.mu.Unlock()
+			return nil
+		} else {
+			delay = time.Duration((tokens - rl.tokens) / rl.rate * float64(time.Second))
+		}
+		rl.mu.Unlock()
+
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+func (rl *RateLimiter) refill(now time.Time) {
+	rl.tokens += now.Sub(rl.last).Seconds() * rl.rate
+	if rl.tokens > rl.burst {
+		rl.tokens = rl.burst
+	}
+	rl.last = now
+}
+
+// throttled is called when the API responds with 429.
+func (rl *RateLimiter) throttled(pause time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	now := time.Now()
+	rl.refill(now)
+	rl.tokens = 0
+	if rl.rate /= 2; rl.rate < rl.limit/16 {
+		rl.rate = rl.limit / 16
+	}
+	if until := now.Add(pause); until.After(rl.pausedUntil) {
+		rl.pausedUntil = until
+	}
+}
+
+// succeeded is called for every response that wasn't a 429, and moves the rate back towards the configured limit.
+func (rl *RateLimiter) succeeded() {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	if rl.rate < rl.limit {
+		rl.refill(time.Now())
+		if rl.rate += rl.limit / 20; rl.rate > rl.limit {
+			rl.rate = rl.limit
+		}
+	}
+}
+
+// throttledReader limits how fast bytes can be read from r.
+type throttledReader struct {
+	ctx     context.Context
+	r       io.Reader
+	limiter *RateLimiter
+}
+
+func (tr *throttledReader) Read(p []byte) (int, error) {
+	// Reading more than a burst's worth at once would let a single read exceed the limit.
+	if max := int(tr.limiter.burst); len(p) > max {
+		p = p[:max]
+	}
+	n, err := tr.r.Read(p)
+	if n > 0 {
+		if waitErr := tr.limiter.WaitN(tr.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
+
+// rateLimiterFor returns the RateLimiter that req waits for, or nil if it shouldn't wait. Only requests to the API and
+// upload endpoints do: other hosts, such as Wistia's CDN or a server a media is imported from, have limits of their
+// own, and a 429 from them mustn't slow down the API requests.
+func (c *Client) rateLimiterFor(req *http.Request) *RateLimiter {
+	if c.RateLimiter == nil || (!isEndpointHost(req.URL, c.APIBaseEndpoint) && !isEndpointHost(req.URL, c.UploadBaseEndpoint)) {
+		return nil
+	}
+	return c.RateLimiter
+}
+
+func isEndpointHost(u *url.URL, endpoint string) bool {
+	endpointUrl, err := url.Parse(endpoint)
+	return err == nil && endpointUrl.Host == u.Host
+}
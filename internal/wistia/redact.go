@@ -0,0 +1,92 @@
+package wistia
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const redacted = "REDACTED"
+
+var (
+	bearerPattern      = regexp.MustCompile(`(?i)(bearer\s+)[^\s"',;]+`)
+	accessTokenPattern = regexp.MustCompile(`(?i)(access_token"?\s*[=:]\s*"?)[^\s"&,;}]+`)
+	sensitiveHeaders   = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+)
+
+// redactor masks credentials in anything that's about to be logged. Besides the patterns that credentials usually
+// appear in, it masks every access token the client has handed out verbatim.
+type redactor struct {
+	mu     sync.RWMutex
+	tokens map[string]struct{}
+}
+
+func (r *redactor) addToken(token string) {
+	if token == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.tokens == nil {
+		r.tokens = map[string]struct{}{}
+	}
+	r.tokens[token] = struct{}{}
+}
+
+func (r *redactor) String(s string) string {
+	r.mu.RLock()
+	for token := range r.tokens {
+		s = strings.Replace(s, token, redacted, -1)
+	}
+	r.mu.RUnlock()
+	return redactPatterns(s)
+}
+
+func (r *redactor) Header(h http.Header) http.Header {
+	clone := make(http.Header, len(h))
+	for k, v := range h {
+		values := make([]string, len(v))
+		for i := range v {
+			values[i] = r.String(v[i])
+		}
+		clone[k] = values
+	}
+	for _, k := range sensitiveHeaders {
+		if _, ok := clone[k]; ok {
+			clone[k] = []string{redacted}
+		}
+	}
+	return clone
+}
+
+func (r *redactor) Values(v url.Values) url.Values {
+	clone := make(url.Values, len(v))
+	for k, values := range v {
+		if strings.EqualFold(k, "access_token") {
+			clone[k] = []string{redacted}
+			continue
+		}
+		clone[k] = make([]string, len(values))
+		for i := range values {
+			clone[k][i] = r.String(values[i])
+		}
+	}
+	return clone
+}
+
+func (r *redactor) URL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	clone := *u
+	clone.User = nil
+	clone.RawQuery = r.Values(u.Query()).Encode()
+	return clone.String()
+}
+
+func redactPatterns(s string) string {
+	s = bearerPattern.ReplaceAllString(s, "${1}"+redacted)
+	return accessTokenPattern.ReplaceAllString(s, "${1}"+redacted)
+}
@@ -0,0 +1,180 @@
+package wistia
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how requests that fail with a 429 or 5xx response are retried. Requests that aren't idempotent,
+// such as creating a project or uploading a media, are only retried on responses that show the request wasn't acted
+// on: a 429, a 503, or one that says when to retry with Retry-After.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt. Zero disables retries.
+	MaxRetries int
+	// BaseDelay is the delay before the first retry. It doubles with every further retry.
+	BaseDelay time.Duration
+	// MaxDelay caps the computed backoff delay. A Retry-After sent by the API is honored even if it's longer.
+	MaxDelay time.Duration
+	// Jitter is the fraction (0 to 1) of each delay that's randomized to spread out concurrent retries.
+	Jitter float64
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 4,
+	BaseDelay:  1 * time.Second,
+	MaxDelay:   30 * time.Second,
+	Jitter:     0.2,
+}
+
+func (rp RetryPolicy) backoff(retry int) time.Duration {
+	delay := rp.BaseDelay
+	for i := 1; i < retry && delay < rp.MaxDelay; i++ {
+		delay *= 2
+	}
+	if rp.MaxDelay > 0 && delay > rp.MaxDelay {
+		delay = rp.MaxDelay
+	}
+	if rp.Jitter > 0 {
+		delay -= time.Duration(rp.Jitter * rand.Float64() * float64(delay))
+	}
+	return delay
+}
+
+func isRetryable(req *http.Request, resp *http.Response) bool {
+	status := resp.StatusCode
+	if status == http.StatusTooManyRequests {
+		return true
+	}
+	if req.Context().Value(onlyThrottledRetriesKey{}) != nil {
+		return false
+	}
+	if isIdempotent(req.Method) {
+		return status >= http.StatusInternalServerError && status != http.StatusNotImplemented
+	}
+	// Other 5xx responses can come after the server has already created something, so retrying could create it twice.
+	_, hasRetryAfter := retryAfter(resp)
+	return status == http.StatusServiceUnavailable || (status >= http.StatusInternalServerError && hasRetryAfter)
+}
+
+// isRetryableTransportError reports whether req can be sent again after it failed without a response. Only requests
+// whose body can be replayed are, since those are the ones that carry a lot of data, like uploads.
+func isRetryableTransportError(req *http.Request) bool {
+	return req.GetBody != nil && req.Context().Err() == nil && req.Context().Value(onlyThrottledRetriesKey{}) == nil
+}
+
+type onlyThrottledRetriesKey struct{}
+
+// onlyThrottledRetries marks the requests made with the returned context to only be retried on a 429, which the API
+// sends before acting on a request. It's for requests that cost money if they're carried out twice.
+func onlyThrottledRetries(ctx context.Context) context.Context {
+	return context.WithValue(ctx, onlyThrottledRetriesKey{}, true)
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses the Retry-After header, which holds either a number of seconds or an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date), true
+	}
+	return 0, false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// do sends req with httpClient and retries it according to the client's RetryPolicy. A request with a body is only retried if the
+// body can be replayed through req.GetBody; otherwise the first response is returned as-is. Such requests, e.g. file
+// uploads, are also retried when the connection fails, say because it was reset part way through. Every attempt to the
+// API or upload endpoints waits for the client's RateLimiter, if it has one.
+func (c *Client) do(httpClient *http.Client, req *http.Request) (*http.Response, error) {
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	limiter := c.rateLimiterFor(req)
+	for retry := 1; ; retry++ {
+		if limiter != nil {
+			if err := limiter.Wait(req.Context()); err != nil {
+				if req.Body != nil {
+					_ = req.Body.Close()
+				}
+				return nil, err
+			}
+		}
+		delay := c.RetryPolicy.backoff(retry)
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			if !isRetryableTransportError(req) || retry > c.RetryPolicy.MaxRetries {
+				return nil, err
+			}
+			log.Printf("[DEBUG] %s %s failed: %s; retry %d of %d in %s",
+				req.Method, req.URL.Host+req.URL.Path, err, retry, c.RetryPolicy.MaxRetries, delay)
+		} else {
+			if limiter != nil {
+				if resp.StatusCode == http.StatusTooManyRequests {
+					pause, _ := retryAfter(resp)
+					limiter.throttled(pause)
+				} else {
+					limiter.succeeded()
+				}
+			}
+			if !replayable || retry > c.RetryPolicy.MaxRetries || !isRetryable(req, resp) {
+				return resp, nil
+			}
+
+			if d, ok := retryAfter(resp); ok && d > delay {
+				delay = d
+			}
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			_ = resp.Body.Close()
+
+			log.Printf("[DEBUG] %s %s responded with status %d; retry %d of %d in %s",
+				req.Method, req.URL.Host+req.URL.Path, resp.StatusCode, retry, c.RetryPolicy.MaxRetries, delay)
+		}
+		if err := sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+
+		// The token may have expired while waiting.
+		if req.Header.Get("Authorization") != "" {
+			if err := c.authorize(req); err != nil {
+				return nil, err
+			}
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"io/ioutil"
 	"log"
 	"net/http"
+
+	"golang.org/x/oauth2"
 )
 
 const (
@@ -17,36 +19,58 @@ const (
 	defaultUserAgent      = "wistia-go-client/1.0"
 )
 
+// TokenSource supplies the access token used to authenticate requests. Any oauth2.TokenSource satisfies it, so
+// tokens obtained through an OAuth2 flow are refreshed when they expire.
+type TokenSource interface {
+	Token() (*oauth2.Token, error)
+}
+
+// StaticTokenSource returns a TokenSource for a long-lived access token.
+func StaticTokenSource(accessToken string) TokenSource {
+	return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: accessToken})
+}
+
 type Client struct {
-	accessToken string // TODO: use oauth2 package?
+	tokenSource TokenSource
 	httpClient  *http.Client
+	redact      redactor
 
 	APIBaseEndpoint    string
 	UploadBaseEndpoint string
+	RetryPolicy        RetryPolicy
+	RateLimiter        *RateLimiter
+	// UploadHTTPClient is used for requests to the upload endpoint, which can take far longer than API requests.
+	// If it's nil, the client passed to NewClient is used.
+	UploadHTTPClient *http.Client
+	// UploadBandwidthLimiter, if set, caps the combined bandwidth of all file uploads, in bytes per second.
+	UploadBandwidthLimiter *RateLimiter
 
 	Media          *MediaProvider
 	Projects       *ProjectsProvider
 	Customizations *CustomizationsProvider
+	Captions       *CaptionsProvider
 }
 
 type provider struct {
 	client *Client
 }
 
-func NewClient(httpClient *http.Client, accessToken string) *Client {
+func NewClient(httpClient *http.Client, tokenSource TokenSource) *Client {
 	if httpClient == nil {
 		httpClient = http.DefaultClient
 	}
 
 	client := &Client{
-		accessToken:        accessToken,
+		tokenSource:        oauth2.ReuseTokenSource(nil, tokenSource),
 		httpClient:         httpClient,
 		APIBaseEndpoint:    defaultAPIEndpoint,
 		UploadBaseEndpoint: defaultUploadEndpoint,
+		RetryPolicy:        DefaultRetryPolicy,
 	}
 	client.Media = &MediaProvider{client}
 	client.Projects = &ProjectsProvider{client}
 	client.Customizations = &CustomizationsProvider{client}
+	client.Captions = &CaptionsProvider{client}
 	return client
 }
 
@@ -63,11 +87,38 @@ func (c *Client) newRequest(ctx context.Context, method, url string) (*http.Requ
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	if err := c.authorize(req); err != nil {
+		return nil, err
+	}
 	req.Header.Set("User-Agent", defaultUserAgent)
 	return req, nil
 }
 
+func (c *Client) uploadHTTPClient() *http.Client {
+	if c.UploadHTTPClient != nil {
+		return c.UploadHTTPClient
+	}
+	return c.httpClient
+}
+
+func (c *Client) accessToken() (string, error) {
+	token, err := c.tokenSource.Token()
+	if err != nil {
+		return "", fmt.Errorf("couldn't get a Wistia access token: %s", err)
+	}
+	c.redact.addToken(token.AccessToken)
+	return token.AccessToken, nil
+}
+
+func (c *Client) authorize(req *http.Request) error {
+	accessToken, err := c.accessToken()
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	return nil
+}
+
 func (c *Client) doRequest(req *http.Request, body interface{}, responseType interface{}) (*http.Response, error) {
 	if body != nil {
 		req.Header.Set("Content-type", "application/json")
@@ -75,10 +126,13 @@ func (c *Client) doRequest(req *http.Request, body interface{}, responseType int
 		if err != nil {
 			return nil, fmt.Errorf("couldn't marshal json for body: %s", err)
 		}
-		log.Printf("[TRACE] Request body: %s", string(payload))
+		log.Printf("[TRACE] Request body: %s", c.redact.String(string(payload)))
 		req.Body = ioutil.NopCloser(bytes.NewReader(payload))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(payload)), nil
+		}
 	}
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(c.httpClient, req)
 	if err != nil {
 		return nil, err
 	}
@@ -92,10 +146,11 @@ func (c *Client) doRequest(req *http.Request, body interface{}, responseType int
 		return resp, fmt.Errorf("failed to read response body: %s", err)
 	}
 
-	log.Printf("[TRACE] API response: %v; body: %s", resp, respBody)
+	log.Printf("[TRACE] API request: %s %s; headers: %v", req.Method, c.redact.URL(req.URL), c.redact.Header(req.Header))
+	log.Printf("[TRACE] API response: %s; headers: %v; body: %s", resp.Status, c.redact.Header(resp.Header), c.redact.String(string(respBody)))
 
 	if resp.StatusCode >= http.StatusBadRequest {
-		return resp, fmt.Errorf("the Wistia API responded with status %d and body %s", resp.StatusCode, string(respBody))
+		return resp, newAPIError(resp, respBody)
 	}
 
 	if responseType != nil {